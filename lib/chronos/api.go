@@ -0,0 +1,348 @@
+package chronos
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TasksEndpoint is an endpoint of the API to list tasks.
+	// The status of each task is available on `TasksEndpoint/<task name>`.
+	TasksEndpoint = "/api/v1/tasks"
+
+	// TriggerAction is the action to execute a task immediately.
+	// It is invoked by POST method on `TasksEndpoint/<task name>/trigger`.
+	// If `wait` query parameter is `true`, the API responds after the execution finished.
+	TriggerAction = "trigger"
+
+	// PauseAction is the action to pause the scheduled executions of a task.
+	// It is invoked by POST method on `TasksEndpoint/<task name>/pause`.
+	PauseAction = "pause"
+	// ResumeAction is the action to resume the scheduled executions of a paused task.
+	// It is invoked by POST method on `TasksEndpoint/<task name>/resume`.
+	ResumeAction = "resume"
+
+	// defaultExecutionLimit is the number of recent executions returned by the API by default.
+	defaultExecutionLimit = 10
+	// redactedValue is the value to be shown instead of the secrets. (e.g. the values of environment variables)
+	redactedValue = "<redacted>"
+)
+
+// TaskStatus is the status of a task returned by the API.
+type TaskStatus struct {
+	// Name is the name of the task.
+	Name string `json:"name"`
+	// Task is the config of the task. The values of `Env` are redacted.
+	Task *Task `json:"task"`
+	// Schedule is the specification of the schedule of the task.
+	Schedule string `json:"schedule"`
+	// Next is the time when the task will be executed next. It is nil if the worker is not running.
+	Next *time.Time `json:"next,omitempty"`
+	// Prev is the time when the task was executed last by the scheduler.
+	// It is nil if the task has not been executed since the worker started.
+	Prev *time.Time `json:"prev,omitempty"`
+	// State is the state of the task. (`healthy` or `unhealthy`)
+	State string `json:"state"`
+	// Reason is the reason why the task is unhealthy.
+	Reason string `json:"reason,omitempty"`
+	// Paused is true if the scheduled executions of the task are paused.
+	Paused bool `json:"paused"`
+	// Window is the state of the task against its active period. (`pending`, `active` or `expired`)
+	Window WindowState `json:"window"`
+	// Upstream are the tasks which trigger the task on their completion.
+	Upstream []*Dependency `json:"upstream,omitempty"`
+	// Downstream are the tasks triggered by the completion of the task.
+	Downstream []*Dependency `json:"downstream,omitempty"`
+	// Executions are the recent executions of the task in chronological order.
+	Executions []*Execution `json:"executions"`
+}
+
+// TriggerResult is the result of the API to trigger a task.
+type TriggerResult struct {
+	// Name is the name of the triggered task.
+	Name string `json:"name"`
+	// Execution is the last execution of the task in the series of retry.
+	// It is nil unless the API was invoked with waiting for the completion.
+	Execution *Execution `json:"execution,omitempty"`
+}
+
+type errorResult struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		_, _ = rw.Write([]byte(fmt.Sprintf("failed to marshal JSON. err: %s", err)))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_, _ = rw.Write(b)
+}
+
+func writeError(rw http.ResponseWriter, status int, format string, v ...interface{}) {
+	writeJSON(rw, status, &errorResult{Error: fmt.Sprintf(format, v...)})
+}
+
+// redactTask returns a copy of the task whose values of environment variables and webhooks are redacted.
+func redactTask(t *Task) *Task {
+	redacted := *t
+	redacted.Env = redactValues(t.Env)
+	if t.Notification != nil {
+		redacted.Notification = &Notification{Webhooks: make([]*Webhook, 0, len(t.Notification.Webhooks))}
+		for _, hook := range t.Notification.Webhooks {
+			h := *hook
+			h.URL = redactURL(hook.URL)
+			h.Headers = redactValues(hook.Headers)
+			redacted.Notification.Webhooks = append(redacted.Notification.Webhooks, &h)
+		}
+	}
+	return &redacted
+}
+
+// redactValues returns a copy of the map whose values are redacted.
+func redactValues(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(m))
+	for k := range m {
+		redacted[k] = redactedValue
+	}
+	return redacted
+}
+
+// redactURL returns the URL whose credentials, path, query and fragment are redacted.
+// Only the scheme and the host are left, since the others may contain secrets. (e.g. the token of Slack)
+func redactURL(v string) string {
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" {
+		return redactedValue
+	}
+	if u.User == nil && (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.Fragment == "" {
+		return u.Scheme + "://" + u.Host
+	}
+	return u.Scheme + "://" + u.Host + "/" + redactedValue
+}
+
+// taskStatus returns the status of the Job with the recent `limit` executions.
+func (w *Worker) taskStatus(j *Job, limit int) (*TaskStatus, error) {
+	executions, err := j.History(limit)
+	if err != nil {
+		return nil, err
+	}
+	if executions == nil {
+		executions = []*Execution{}
+	}
+
+	state, reason := w.health(j)
+	status := &TaskStatus{
+		Name:       j.name,
+		Task:       redactTask(j.task),
+		Schedule:   j.task.Schedule,
+		State:      state.String(),
+		Reason:     reason,
+		Paused:     j.IsPaused(),
+		Window:     j.task.windowState(time.Now()),
+		Executions: executions,
+	}
+	status.Upstream, status.Downstream = w.dependencies(j.name)
+
+	if e := w.entry(j); e != nil {
+		if !e.Next.IsZero() {
+			next := e.Next
+			status.Next = &next
+		}
+		if !e.Prev.IsZero() {
+			prev := e.Prev
+			status.Prev = &prev
+		}
+	}
+	return status, nil
+}
+
+func parseExecutionLimit(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return defaultExecutionLimit, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("`limit` must be a non-negative integer. got: %s", v)
+	}
+	return limit, nil
+}
+
+func (w *Worker) tasksHandler(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(rw, http.StatusMethodNotAllowed, "method %s is not allowed", r.Method)
+		return
+	}
+	limit, err := parseExecutionLimit(r)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	jobs := w.Jobs()
+	sort.Slice(jobs, func(a, b int) bool {
+		return jobs[a].name < jobs[b].name
+	})
+	res := make([]*TaskStatus, 0, len(jobs))
+	for _, j := range jobs {
+		status, err := w.taskStatus(j, limit)
+		if err != nil {
+			writeError(rw, http.StatusInternalServerError, "failed to get the status of Task `%s`: %s", j.name, err)
+			return
+		}
+		res = append(res, status)
+	}
+	writeJSON(rw, http.StatusOK, res)
+}
+
+func (w *Worker) taskHandler(rw http.ResponseWriter, r *http.Request) {
+	// use escaped path to accept task names including slashes
+	parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), TasksEndpoint+"/"), "/")
+	name, err := url.PathUnescape(parts[0])
+	if err != nil || name == "" || len(parts) > 2 {
+		writeError(rw, http.StatusNotFound, "not found")
+		return
+	}
+	j := w.Job(name)
+	if j == nil {
+		writeError(rw, http.StatusNotFound, "Task `%s` is not found", name)
+		return
+	}
+
+	if len(parts) == 2 {
+		switch parts[1] {
+		case TriggerAction:
+			w.triggerHandler(rw, r, j)
+		case PauseAction:
+			w.pauseHandler(rw, r, j, true)
+		case ResumeAction:
+			w.pauseHandler(rw, r, j, false)
+		default:
+			writeError(rw, http.StatusNotFound, "not found")
+		}
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(rw, http.StatusMethodNotAllowed, "method %s is not allowed", r.Method)
+		return
+	}
+	limit, err := parseExecutionLimit(r)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, "%s", err)
+		return
+	}
+	status, err := w.taskStatus(j, limit)
+	if err != nil {
+		writeError(rw, http.StatusInternalServerError, "failed to get the status of Task `%s`: %s", j.name, err)
+		return
+	}
+	writeJSON(rw, http.StatusOK, status)
+}
+
+// authorize checks the bearer token of the request.
+// It writes an error response and returns false when the request is not authorized.
+func (w *Worker) authorize(rw http.ResponseWriter, r *http.Request) bool {
+	token := ""
+	if conf := w.config(); conf.HealthCheck != nil {
+		token = conf.HealthCheck.Token
+	}
+	if token == "" {
+		writeError(rw, http.StatusForbidden, "this API is disabled because token is not configured")
+		return false
+	}
+
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(rw, http.StatusUnauthorized, "unauthorized")
+		return false
+	}
+	return true
+}
+
+func (w *Worker) triggerHandler(rw http.ResponseWriter, r *http.Request, j *Job) {
+	if r.Method != http.MethodPost {
+		writeError(rw, http.StatusMethodNotAllowed, "method %s is not allowed", r.Method)
+		return
+	}
+	if !w.authorize(rw, r) {
+		return
+	}
+	wait, err := strconv.ParseBool(r.URL.Query().Get("wait"))
+	if err != nil && r.URL.Query().Get("wait") != "" {
+		writeError(rw, http.StatusBadRequest, "`wait` must be a boolean. got: %s", r.URL.Query().Get("wait"))
+		return
+	}
+
+	ctx, ok := w.startRun()
+	if !ok {
+		writeError(rw, http.StatusServiceUnavailable, "worker is shutting down")
+		return
+	}
+	w.logger.Infof("Task `%s` was triggered manually.", j.name)
+	done := make(chan *Execution, 1)
+	go func() {
+		defer w.runs.Done()
+		done <- j.Trigger(ctx)
+	}()
+
+	if !wait {
+		writeJSON(rw, http.StatusAccepted, &TriggerResult{Name: j.name})
+		return
+	}
+	select {
+	case execution := <-done:
+		writeJSON(rw, http.StatusOK, &TriggerResult{Name: j.name, Execution: execution})
+	case <-r.Context().Done():
+		// the execution continues even if the client disconnected.
+	}
+}
+
+func (w *Worker) pauseHandler(rw http.ResponseWriter, r *http.Request, j *Job, pause bool) {
+	if r.Method != http.MethodPost {
+		writeError(rw, http.StatusMethodNotAllowed, "method %s is not allowed", r.Method)
+		return
+	}
+	if !w.authorize(rw, r) {
+		return
+	}
+
+	var err error
+	if pause {
+		err = j.Pause()
+	} else {
+		err = j.Resume()
+	}
+	if err != nil {
+		writeError(rw, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	if pause {
+		w.logger.Infof("Task `%s` has been paused.", j.name)
+	} else {
+		w.logger.Infof("Task `%s` has been resumed.", j.name)
+	}
+
+	status, err := w.taskStatus(j, defaultExecutionLimit)
+	if err != nil {
+		writeError(rw, http.StatusInternalServerError, "failed to get the status of Task `%s`: %s", j.name, err)
+		return
+	}
+	writeJSON(rw, http.StatusOK, status)
+}
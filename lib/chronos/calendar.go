@@ -0,0 +1,283 @@
+package chronos
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calendarEvent is an event in an iCalendar file.
+type calendarEvent struct {
+	summary    string
+	start, end time.Time
+	// allDay is true if the event is specified by dates rather than date-times.
+	allDay bool
+	// rule is the recurrence of the event. It is nil for the event which does not recur.
+	rule *recurrence
+}
+
+// recurrence is the subset of RRULE of iCalendar. `BYxxx` rules are not supported.
+type recurrence struct {
+	freq     string
+	interval int
+	// count is the number of the occurrences. 0 means no limitation.
+	count int
+	// until is the time until which the event recurs. zero value means no limitation.
+	until time.Time
+}
+
+// errUnsupportedRecurrence is the error of RRULE which is not supported. The event with it is ignored.
+var errUnsupportedRecurrence = errors.New("unsupported RRULE")
+
+// icsDurationPattern matches the durations of iCalendar. (e.g. `P1D`, `PT1H30M`)
+var icsDurationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// loadCalendar reads the events from the iCalendar file.
+// The floating times and the dates are interpreted in `loc`.
+// It also returns the descriptions of the events ignored because of the unsupported recurrence.
+func loadCalendar(filename string, loc *time.Location) ([]*calendarEvent, []string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open calendar: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	events, ignored, err := parseCalendar(f, loc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse calendar %s: %w", filename, err)
+	}
+	return events, ignored, nil
+}
+
+// unfoldLines reads the content lines of iCalendar, joining the folded lines.
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseCalendar parses the events in iCalendar format.
+// Only `DTSTART`, `DTEND`, `DURATION`, `RRULE` and `SUMMARY` of `VEVENT` are taken into account.
+// The events with unsupported RRULE are ignored, and their descriptions are returned as `ignored`.
+func parseCalendar(r io.Reader, loc *time.Location) (events []*calendarEvent, ignored []string, err error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var event *calendarEvent
+	var duration string
+	// unsupported is the error of the unsupported RRULE of the current event
+	var unsupported error
+	for i, line := range lines {
+		property, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, rawParams, _ := strings.Cut(property, ";")
+		params := make(map[string]string)
+		for _, p := range strings.Split(rawParams, ";") {
+			if k, v, ok := strings.Cut(p, "="); ok {
+				params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+			}
+		}
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				event, duration, unsupported = &calendarEvent{}, "", nil
+			}
+			continue
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || event == nil {
+				continue
+			}
+			if event.start.IsZero() {
+				return nil, nil, fmt.Errorf("line %d: event without DTSTART", i+1)
+			}
+			if event.end.IsZero() {
+				event.end, err = eventEnd(event, duration)
+				if err != nil {
+					return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+				}
+			}
+			if unsupported != nil {
+				ignored = append(ignored, fmt.Sprintf("event `%s` is ignored: %s", event.summary, unsupported))
+			} else {
+				events = append(events, event)
+			}
+			event = nil
+			continue
+		}
+		if event == nil {
+			continue
+		}
+
+		switch strings.ToUpper(name) {
+		case "SUMMARY":
+			event.summary = value
+		case "DTSTART":
+			event.start, event.allDay, err = parseCalendarTime(value, params, loc)
+		case "DTEND":
+			event.end, _, err = parseCalendarTime(value, params, loc)
+		case "DURATION":
+			duration = value
+		case "RRULE":
+			event.rule, err = parseRecurrence(value, loc)
+			if errors.Is(err, errUnsupportedRecurrence) {
+				unsupported, err = fmt.Errorf("line %d: %w", i+1, err), nil
+			}
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return events, ignored, nil
+}
+
+// parseCalendarTime parses the value of DATE or DATE-TIME.
+// It returns true if the value is DATE.
+func parseCalendarTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("malformed date: %s", value)
+		}
+		return t, true, nil
+	}
+
+	if tzid, ok := params["TZID"]; ok {
+		var err error
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("failed to get timezone: %w", err)
+		}
+	}
+	layout := "20060102T150405"
+	if strings.HasSuffix(value, "Z") {
+		layout, loc = "20060102T150405Z", time.UTC
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("malformed date-time: %s", value)
+	}
+	return t, false, nil
+}
+
+// eventEnd returns the end of the event which does not have DTEND.
+func eventEnd(event *calendarEvent, duration string) (time.Time, error) {
+	if duration == "" {
+		if event.allDay {
+			return event.start.AddDate(0, 0, 1), nil
+		}
+		return event.start, nil
+	}
+
+	m := icsDurationPattern.FindStringSubmatch(duration)
+	if m == nil || duration == "P" || duration == "PT" {
+		return time.Time{}, fmt.Errorf("malformed duration: %s", duration)
+	}
+	n := make([]int, len(m))
+	for i := 1; i < len(m); i++ {
+		n[i], _ = strconv.Atoi(m[i])
+	}
+	end := event.start.AddDate(0, 0, n[1]*7+n[2])
+	return end.Add(time.Duration(n[3])*time.Hour + time.Duration(n[4])*time.Minute + time.Duration(n[5])*time.Second), nil
+}
+
+// parseRecurrence parses the value of RRULE.
+func parseRecurrence(value string, loc *time.Location) (*recurrence, error) {
+	rule := &recurrence{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			switch v {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				rule.freq = v
+			default:
+				return nil, fmt.Errorf("%w: frequency %s", errUnsupportedRecurrence, v)
+			}
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(v)
+			if err == nil && rule.interval <= 0 {
+				err = fmt.Errorf("interval must be positive")
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(v)
+		case "UNTIL":
+			rule.until, _, err = parseCalendarTime(v, nil, loc)
+		case "WKST":
+			// it affects only `BYxxx` rules
+		default:
+			return nil, fmt.Errorf("%w: %s", errUnsupportedRecurrence, part)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed RRULE %s: %w", value, err)
+		}
+	}
+	if rule.freq == "" {
+		return nil, fmt.Errorf("RRULE without FREQ: %s", value)
+	}
+	return rule, nil
+}
+
+// occurrence returns the start of the `n`-th occurrence of the event.
+func (e *calendarEvent) occurrence(n int) time.Time {
+	k := n * e.rule.interval
+	switch e.rule.freq {
+	case "DAILY":
+		return e.start.AddDate(0, 0, k)
+	case "WEEKLY":
+		return e.start.AddDate(0, 0, 7*k)
+	case "MONTHLY":
+		return e.start.AddDate(0, k, 0)
+	default:
+		return e.start.AddDate(k, 0, 0)
+	}
+}
+
+// contains returns true if `t` is during the event or any of its occurrences.
+func (e *calendarEvent) contains(t time.Time) bool {
+	if e.rule == nil {
+		return !t.Before(e.start) && t.Before(e.end)
+	}
+	length := e.end.Sub(e.start)
+	for n := 0; e.rule.count == 0 || n < e.rule.count; n++ {
+		start := e.occurrence(n)
+		if start.After(t) || (!e.rule.until.IsZero() && start.After(e.rule.until)) {
+			return false
+		}
+		if start.Day() != e.start.Day() && (e.rule.freq == "MONTHLY" || e.rule.freq == "YEARLY") {
+			// the day does not exist in the month (e.g. 31st in April)
+			continue
+		}
+		end := start.Add(length)
+		if e.allDay {
+			end = start.AddDate(0, 0, int(length.Hours()+12)/24)
+		}
+		if t.Before(end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package chronos
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:New Year's Day\r\n" +
+	"DTSTART;VALUE=DATE:20240101\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Coming of Age Day\r\n" +
+	"DTSTART;VALUE=DATE:20240108\r\n" +
+	"RRULE:FREQ=YEARLY;BYMONTH=1;BYDAY=2MO\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Maintenance\r\n" +
+	"  of database\r\n" +
+	"DTSTART;TZID=Asia/Tokyo:20240601T020000\r\n" +
+	"DURATION:PT2H\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Release freeze\r\n" +
+	"DTSTART:20240610T000000Z\r\n" +
+	"DTEND:20240612T000000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Monthly close\r\n" +
+	"DTSTART;VALUE=DATE:20240131\r\n" +
+	"RRULE:FREQ=MONTHLY;COUNT=3\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseCalendar(t *testing.T) {
+	events, ignored, err := parseCalendar(strings.NewReader(testCalendar), time.UTC)
+	if err != nil {
+		t.Fatalf("failed to parse calendar: %s", err)
+	}
+	if want := []string{"event `Coming of Age Day` is ignored: line 11: unsupported RRULE: BYMONTH=1"}; !reflect.DeepEqual(ignored, want) {
+		t.Errorf("unexpected ignored events. want: %q, got: %q", want, ignored)
+	}
+	if len(events) != 4 {
+		t.Fatalf("unexpected number of events. got: %d", len(events))
+	}
+	if events[1].summary != "Maintenance of database" {
+		t.Errorf("folded line must be unfolded. got: %s", events[1].summary)
+	}
+
+	tests := []struct {
+		time time.Time
+		want string
+	}{
+		{time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), want: "New Year's Day"},
+		{time: time.Date(2030, 1, 1, 23, 59, 59, 0, time.UTC), want: "New Year's Day"},
+		{time: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC), want: ""},
+		{time: time.Date(2024, 5, 31, 17, 30, 0, 0, time.UTC), want: "Maintenance of database"},
+		{time: time.Date(2024, 5, 31, 19, 0, 0, 0, time.UTC), want: ""},
+		{time: time.Date(2024, 6, 11, 23, 59, 0, 0, time.UTC), want: "Release freeze"},
+		{time: time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC), want: "Monthly close"},
+		// February does not have the 31st
+		{time: time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC), want: ""},
+		{time: time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC), want: "Monthly close"},
+		// beyond COUNT
+		{time: time.Date(2024, 5, 31, 9, 0, 0, 0, time.UTC), want: ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		for _, e := range events {
+			if e.contains(tt.time) {
+				got = e.summary
+				break
+			}
+		}
+		if got != tt.want {
+			t.Errorf("unexpected event at %s. want: %q, got: %q", tt.time, tt.want, got)
+		}
+	}
+}
+
+func TestParseCalendarError(t *testing.T) {
+	for _, ics := range []string{
+		"BEGIN:VEVENT\nSUMMARY:no start\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:2024-01-01\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20240101T000000Z\nDURATION:1H\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20240101T000000Z\nRRULE:INTERVAL=0\nEND:VEVENT\n",
+	} {
+		if _, _, err := parseCalendar(strings.NewReader(ics), time.UTC); err == nil {
+			t.Errorf("expected error for %q", ics)
+		}
+	}
+}
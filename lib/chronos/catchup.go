@@ -0,0 +1,101 @@
+package chronos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// DefaultMaxCatchup is the maximum number of the missed executions to be executed when `Task.MaxCatchup` is not specified.
+const DefaultMaxCatchup = 10
+
+// maxCatchup returns the maximum number of the missed executions to be executed.
+func (t *Task) maxCatchup() int {
+	switch t.Catchup {
+	case CatchupPolicyAll:
+		if t.MaxCatchup > 0 {
+			return t.MaxCatchup
+		}
+		return DefaultMaxCatchup
+	case CatchupPolicyLatest:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// missed returns the times when the task was scheduled after `last` until `now`, which should be caught up.
+// The times are limited by `Catchup`, `MaxCatchup` and `StartingDeadline`, and sorted in chronological order.
+func (t *Task) missed(schedule cron.Schedule, last time.Time, now time.Time) []time.Time {
+	limit := t.maxCatchup()
+	if limit == 0 || last.IsZero() {
+		return nil
+	}
+	if t.StartingDeadline > 0 {
+		deadline := now.Add(-time.Duration(t.StartingDeadline) * time.Second)
+		if last.Before(deadline) {
+			// the times before the deadline are not caught up, and the one at the deadline is
+			last = deadline.Add(-time.Nanosecond)
+		}
+	}
+
+	// keep only the latest `limit` times
+	var times []time.Time
+	for next := schedule.Next(last); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		times = append(times, next)
+		if len(times) > limit {
+			times = times[1:]
+		}
+	}
+	return times
+}
+
+// lastScheduledAt returns the time when the last scheduled execution in the history was scheduled.
+// The manual executions are ignored. It returns zero value if the task has never been scheduled.
+func (j *Job) lastScheduledAt() (time.Time, error) {
+	executions, err := j.history.List(j.name, 0)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get the history of Task `%s`: %w", j.name, err)
+	}
+	for i := len(executions) - 1; i >= 0; i-- {
+		e := executions[i]
+		if e.Trigger == TriggerManual {
+			continue
+		}
+		if !e.ScheduledAt.IsZero() {
+			return e.ScheduledAt, nil
+		}
+		// the executions recorded before `ScheduledAt` was introduced
+		return e.StartedAt, nil
+	}
+	return time.Time{}, nil
+}
+
+// catchup executes the executions of the Job missed until `now` one by one according to `Task.Catchup`.
+func (s *scheduledJob) catchup(schedule cron.Schedule, now time.Time) {
+	j := s.job
+	if j.task.maxCatchup() == 0 {
+		return
+	}
+	last, err := j.lastScheduledAt()
+	if err != nil {
+		j.logger.Warnf("Task `%s` failed to determine the missed executions: %s", j.name, err)
+		return
+	}
+	times := j.task.missed(schedule, last, now)
+	if len(times) == 0 {
+		return
+	}
+	j.logger.Infof("Task `%s` will catch up %d missed execution(s) since %s", j.name, len(times), last)
+
+	for _, scheduledAt := range times {
+		ctx, ok := s.w.startRun()
+		if !ok {
+			return
+		}
+		j.logger.Infof("Task `%s` catches up the execution scheduled at %s", j.name, scheduledAt)
+		j.runUnlessPaused(ctx, runCause{trigger: TriggerCatchup, scheduledAt: scheduledAt})
+		s.w.runs.Done()
+	}
+}
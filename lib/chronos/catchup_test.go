@@ -0,0 +1,120 @@
+package chronos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestTaskMissed(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 30, 0, 0, time.UTC)
+	last := time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time {
+		return time.Date(2024, 1, 10, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		task *Task
+		last time.Time
+		want []time.Time
+	}{
+		{
+			name: "none",
+			task: &Task{},
+			last: last,
+			want: nil,
+		},
+		{
+			name: "latest",
+			task: &Task{Catchup: CatchupPolicyLatest},
+			last: last,
+			want: []time.Time{at(12)},
+		},
+		{
+			name: "all",
+			task: &Task{Catchup: CatchupPolicyAll},
+			last: last,
+			want: []time.Time{at(9), at(10), at(11), at(12)},
+		},
+		{
+			name: "all with max_catchup",
+			task: &Task{Catchup: CatchupPolicyAll, MaxCatchup: 2},
+			last: last,
+			want: []time.Time{at(11), at(12)},
+		},
+		{
+			name: "all with starting_deadline",
+			task: &Task{Catchup: CatchupPolicyAll, StartingDeadline: 3 * 60 * 60},
+			last: last,
+			want: []time.Time{at(10), at(11), at(12)},
+		},
+		{
+			name: "latest beyond starting_deadline",
+			task: &Task{Catchup: CatchupPolicyLatest, StartingDeadline: 10 * 60},
+			last: last,
+			want: nil,
+		},
+		{
+			name: "never scheduled",
+			task: &Task{Catchup: CatchupPolicyAll},
+			last: time.Time{},
+			want: nil,
+		},
+		{
+			name: "nothing missed",
+			task: &Task{Catchup: CatchupPolicyAll},
+			last: at(12),
+			want: nil,
+		},
+	}
+
+	schedule, err := parseCronSpec("0 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.task.missed(schedule, tt.last, now)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected missed times (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJobLastScheduledAt(t *testing.T) {
+	store := NewMemoryHistoryStore()
+	j := NewJob("test", &Task{Command: "true"}, store, &logger.NopLogger{})
+
+	got, err := j.lastScheduledAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsZero() {
+		t.Errorf("expected zero value for the task never scheduled. got: %s", got)
+	}
+
+	scheduledAt := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	j.run(context.Background(), runCause{trigger: TriggerCatchup, scheduledAt: scheduledAt})
+	j.Trigger(context.Background())
+
+	got, err = j.lastScheduledAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(scheduledAt) {
+		t.Errorf("unexpected last scheduled time. want: %s, got: %s", scheduledAt, got)
+	}
+
+	executions, err := j.History(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := executions[0]; e.Trigger != TriggerCatchup || !e.ScheduledAt.Equal(scheduledAt) {
+		t.Errorf("unexpected catch-up execution: %+v", e)
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // Client it an HTTP Client for HealthCheck.
 type Client struct {
 	httpClient *http.Client
+	token      string
 }
 
 // NewClient returns an instance of Client.
@@ -21,6 +23,15 @@ func NewClient(httpClient *http.Client) *Client {
 	}
 }
 
+// WithToken returns a copy of the Client which sends `token` as the bearer token.
+// The token is required to invoke the APIs which operate tasks.
+func (c *Client) WithToken(token string) *Client {
+	return &Client{
+		httpClient: c.httpClient,
+		token:      token,
+	}
+}
+
 func (c *Client) getClient() *http.Client {
 	if c.httpClient == nil {
 		return http.DefaultClient
@@ -60,3 +71,114 @@ func (c *Client) CheckHealth(ctx context.Context, u *url.URL) (bool, error) {
 
 	return healthCheckResult.OK, nil
 }
+
+// endpoint returns the URL of the endpoint on the worker at `base`.
+// The path of `base` is treated as a prefix.
+func endpoint(base *url.URL, path string, segments ...string) *url.URL {
+	u := *base
+	u.RawQuery = ""
+	u.Fragment = ""
+	escaped := make([]string, 0, len(segments)+1)
+	escaped = append(escaped, strings.TrimSuffix(base.EscapedPath(), "/")+path)
+	for _, s := range segments {
+		escaped = append(escaped, url.PathEscape(s))
+	}
+	u.RawPath = strings.Join(escaped, "/")
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	return &u
+}
+
+// doJSON invokes the API and unmarshals the response into `out`.
+// It returns error if the API responded with a status other than 2xx.
+func (c *Client) doJSON(ctx context.Context, method string, u *url.URL, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create a request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.getClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to exec a request for %s: %w", u.Path, err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(res.Body)
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the body of response: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		e := &errorResult{}
+		if err := json.Unmarshal(b, e); err == nil && e.Error != "" {
+			return fmt.Errorf("API responded with status %d: %s", res.StatusCode, e.Error)
+		}
+		return fmt.Errorf("API responded with status %d", res.StatusCode)
+	}
+
+	err = json.Unmarshal(b, out)
+	if err != nil {
+		return fmt.Errorf("malformed response: %w", err)
+	}
+	return nil
+}
+
+// ListTasks invokes the API to list tasks on the Chronos worker at `base`.
+func (c *Client) ListTasks(ctx context.Context, base *url.URL) ([]*TaskStatus, error) {
+	var tasks []*TaskStatus
+	err := c.doJSON(ctx, http.MethodGet, endpoint(base, TasksEndpoint), &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetTask invokes the API to get the status of the task named `name` on the Chronos worker at `base`.
+func (c *Client) GetTask(ctx context.Context, base *url.URL, name string) (*TaskStatus, error) {
+	task := &TaskStatus{}
+	err := c.doJSON(ctx, http.MethodGet, endpoint(base, TasksEndpoint, name), task)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Trigger invokes the API to execute the task named `name` on the Chronos worker at `base` immediately.
+// If `wait` is true, it waits for the completion of the task and returns the last execution.
+// Otherwise, it returns nil as soon as the task is triggered.
+func (c *Client) Trigger(ctx context.Context, base *url.URL, name string, wait bool) (*Execution, error) {
+	u := endpoint(base, TasksEndpoint, name, TriggerAction)
+	if wait {
+		u.RawQuery = url.Values{"wait": {"true"}}.Encode()
+	}
+	res := &TriggerResult{}
+	err := c.doJSON(ctx, http.MethodPost, u, res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Execution, nil
+}
+
+// Pause invokes the API to pause the scheduled executions of the task named `name` on the Chronos worker at `base`.
+func (c *Client) Pause(ctx context.Context, base *url.URL, name string) (*TaskStatus, error) {
+	task := &TaskStatus{}
+	err := c.doJSON(ctx, http.MethodPost, endpoint(base, TasksEndpoint, name, PauseAction), task)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Resume invokes the API to resume the scheduled executions of the task named `name` on the Chronos worker at `base`.
+func (c *Client) Resume(ctx context.Context, base *url.URL, name string) (*TaskStatus, error) {
+	task := &TaskStatus{}
+	err := c.doJSON(ctx, http.MethodPost, endpoint(base, TasksEndpoint, name, ResumeAction), task)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
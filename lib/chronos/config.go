@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/go-playground/validator/v10"
@@ -40,9 +44,21 @@ type Config struct {
 	// TimeZone is a time-zone which applied to execution time of tasks. By default, use `Local`.
 	TimeZone string `validate:"timezone|isdefault" json:"time_zone" toml:"time_zone" yaml:"time_zone"`
 	// Tasks are the task which executed periodically.
-	Tasks map[string]*Task `validate:"required" json:"tasks" toml:"tasks" yaml:"tasks"`
+	// Each task is validated by the tags of the fields of `Task`, which are not applied to the values of a map without `dive`.
+	Tasks map[string]*Task `validate:"required,dive" json:"tasks" toml:"tasks" yaml:"tasks"`
 	// HealthCheck is the settings for HealthCheck API.
 	HealthCheck *HealthCheck `json:"healthcheck" toml:"healthcheck" yaml:"healthcheck"`
+	// History is the settings for the store of execution history.
+	History *History `json:"history" toml:"history" yaml:"history"`
+	// Notification is the settings for notifications of the results of tasks.
+	// It is applied to the tasks which do not have their own settings.
+	Notification *Notification `json:"notification" toml:"notification" yaml:"notification"`
+	// ShutdownTimeout is the seconds to wait for running tasks to finish on shutdown.
+	// Running tasks receive SIGTERM on shutdown. By default, wait for 30 seconds.
+	ShutdownTimeout int `validate:"gte=0" json:"shutdown_timeout" toml:"shutdown_timeout" yaml:"shutdown_timeout"`
+	// Exclude is the periods in which the executions of all tasks are skipped.
+	// It is applied in addition to the ones of each task.
+	Exclude *Exclusion `json:"exclude" toml:"exclude" yaml:"exclude"`
 }
 
 // NewConfig return the instance of Config.
@@ -70,13 +86,64 @@ func NewConfig(i io.Reader, filename string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	err = validateConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// validateConfig validates the fields of the config and the dependencies between tasks.
+func validateConfig(conf *Config) error {
 	validate := validator.New()
-	err = validate.Struct(conf)
+	// use the names in config files for the error messages
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	_ = validate.RegisterValidation("schedule", func(fl validator.FieldLevel) bool {
+		return validateSchedule(fl.Field().String()) == nil
+	})
+	_ = validate.RegisterValidation("exclusion", func(fl validator.FieldLevel) bool {
+		_, err := parseExclusionSpec(fl.Field().String())
+		return err == nil
+	})
+	_ = validate.RegisterValidation("umask", func(fl validator.FieldLevel) bool {
+		_, err := parseUmask(fl.Field().String())
+		return err == nil
+	})
+	_ = validate.RegisterValidation("signal", func(fl validator.FieldLevel) bool {
+		_, err := (&Task{KillSignal: fl.Field().String()}).killSignal()
+		return err == nil
+	})
+	_ = validate.RegisterValidation("regexp", func(fl validator.FieldLevel) bool {
+		_, err := regexp.Compile(fl.Field().String())
+		return err == nil
+	})
+	err := validate.Struct(conf)
 	if err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
+		return fmt.Errorf("config validation failed: %w", err)
 	}
+	err = validateDependencies(conf.Tasks)
+	if err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	return nil
+}
 
-	return conf, nil
+// LoadConfig reads the config file named `filename` and returns the instance of Config.
+func LoadConfig(filename string) (*Config, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return NewConfig(f, filename)
 }
 
 // HealthCheck is the configuration for HealthCheck server.
@@ -85,6 +152,69 @@ type HealthCheck struct {
 	Host string `validate:"required" json:"host" toml:"host" yaml:"host"`
 	// Port is the TCP port to be used by HealthCheck server, By default, use 8080.
 	Port int `validate:"gt=0,lte=65535" json:"port" toml:"port" yaml:"port"`
+	// Token is the bearer token to authorize the APIs which operate tasks (e.g. triggering a task).
+	// These APIs are disabled when it is empty.
+	Token string `json:"token" toml:"token" yaml:"token"`
+}
+
+// History is the configuration for the store of execution history.
+type History struct {
+	// Driver is the kind of the store. it must be one of `file` and `memory`.
+	// By default, use `file`.
+	Driver HistoryDriver `validate:"oneof=file memory|isdefault" json:"driver" toml:"driver" yaml:"driver"`
+	// Dir is the directory to store the history files for `file` driver.
+	// By default, use `chronos/history` under the user cache directory (e.g. `~/.cache/chronos/history`).
+	// If the user cache directory is not available, the history is kept in memory.
+	Dir string `json:"dir" toml:"dir" yaml:"dir"`
+}
+
+// Notification is the configuration for notifications of the results of tasks.
+type Notification struct {
+	// Webhooks are the webhooks to be notified.
+	Webhooks []*Webhook `validate:"dive" json:"webhooks" toml:"webhooks" yaml:"webhooks"`
+}
+
+// Webhook is the configuration for a webhook which receives notifications as JSON by POST method.
+type Webhook struct {
+	// URL is the URL of the webhook.
+	URL string `validate:"required,url" json:"url" toml:"url" yaml:"url"`
+	// Events are the events to be notified. it must be some of `failure`, `recovery` and `success`.
+	// By default, notify `failure` and `recovery`.
+	// (failure: failed after retries, recovery: succeeded after failure, success: succeeded)
+	Events []NotificationEvent `validate:"dive,oneof=failure recovery success" json:"events" toml:"events" yaml:"events"`
+	// Headers are the HTTP headers given for the request. (e.g. `Authorization`)
+	Headers map[string]string `json:"headers" toml:"headers" yaml:"headers"`
+	// Timeout is the seconds for timeout of the request. By default, use 10 seconds.
+	Timeout int `validate:"gte=0" json:"timeout" toml:"timeout" yaml:"timeout"`
+	// RetryLimit is the count of retry when the request failed. By default, never retry.
+	RetryLimit int `validate:"gte=0" json:"retry_limit" toml:"retry_limit" yaml:"retry_limit"`
+	// RetryWait is the seconds to wait before retry. By default, use 5 seconds.
+	RetryWait int `validate:"gte=0" json:"retry_wait" toml:"retry_wait" yaml:"retry_wait"`
+}
+
+// Exclusion is the configuration for the periods in which the executions of tasks are skipped.
+// (e.g. maintenance windows and public holidays)
+// The executions by the scheduler and the dependencies are skipped, while the manual executions by the API are not.
+type Exclusion struct {
+	// Ranges are the periods to skip the executions.
+	Ranges []*ExclusionRange `validate:"dive" json:"ranges" toml:"ranges" yaml:"ranges"`
+	// Schedules are the cron expressions of the times to skip the executions.
+	// The expressions in 5 fields match every second in the minutes. (e.g. `* 2-4 * * SAT,SUN` for 02:00-04:59 on weekends)
+	// They are evaluated in the time zone of the task.
+	Schedules []string `validate:"dive,exclusion" json:"schedules" toml:"schedules" yaml:"schedules"`
+	// Calendars are the paths to iCalendar (.ics) files. The executions are skipped during their events.
+	// The files are read on every execution, so that they can be updated without reloading the config.
+	// The executions are skipped while any of the files cannot be read.
+	// The events which recur by the rules other than `FREQ`, `INTERVAL`, `COUNT` and `UNTIL` (e.g. `BYDAY`) are ignored.
+	Calendars []string `json:"calendars" toml:"calendars" yaml:"calendars"`
+}
+
+// ExclusionRange is a period to skip the executions.
+type ExclusionRange struct {
+	// Start is the timestamp in RFC 3339 when the period starts.
+	Start string `validate:"required,datetime=2006-01-02T15:04:05Z07:00" json:"start" toml:"start" yaml:"start"`
+	// End is the timestamp in RFC 3339 when the period ends. The period does not include it.
+	End string `validate:"required,datetime=2006-01-02T15:04:05Z07:00" json:"end" toml:"end" yaml:"end"`
 }
 
 // RetryType is the enum of the ways of command retry.
@@ -93,18 +223,64 @@ type RetryType string
 const (
 	// RetryTypeFixed is the kind of retry, which retries on fixed interval.
 	RetryTypeFixed RetryType = "fixed"
+	// RetryTypeLinear is the kind of retry, which retries on linear backoff.
+	RetryTypeLinear RetryType = "linear"
 	// RetryTypeExponential is the kind of retry, which retries on exponential backoff.
 	RetryTypeExponential RetryType = "exponential"
 )
 
+// RetryJitter is the enum of the ways to randomize the wait before retry.
+type RetryJitter string
+
+const (
+	// RetryJitterNone is the default value of `RetryJitter`, which does not randomize the wait.
+	RetryJitterNone RetryJitter = ""
+	// RetryJitterFull is the jitter to wait random duration between 0 and the backoff.
+	RetryJitterFull RetryJitter = "full"
+	// RetryJitterEqual is the jitter to wait the half of the backoff plus random duration up to the other half.
+	RetryJitterEqual RetryJitter = "equal"
+	// RetryJitterDecorrelated is the jitter to wait random duration between `Task.RetryWait` and 3 times the previous wait.
+	RetryJitterDecorrelated RetryJitter = "decorrelated"
+)
+
+// ConcurrencyPolicy is the enum of the ways to handle overlapping executions of a task.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyUnknown is the default value of `ConcurrencyPolicy`.
+	// It is same to specify `allow`.
+	ConcurrencyPolicyUnknown ConcurrencyPolicy = ""
+	// ConcurrencyPolicyAllow is the policy to start a new execution even if the previous one is still running.
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "allow"
+	// ConcurrencyPolicyForbid is the policy to skip a new execution while the previous one is still running.
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "forbid"
+	// ConcurrencyPolicyReplace is the policy to cancel the running execution and start a new one.
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "replace"
+)
+
+// CatchupPolicy is the enum of the ways to handle the executions missed while the worker was down.
+type CatchupPolicy string
+
+const (
+	// CatchupPolicyUnknown is the default value of `CatchupPolicy`.
+	// It is same to specify `none`.
+	CatchupPolicyUnknown CatchupPolicy = ""
+	// CatchupPolicyNone is the policy not to execute the missed executions.
+	CatchupPolicyNone CatchupPolicy = "none"
+	// CatchupPolicyLatest is the policy to execute only the latest one of the missed executions.
+	CatchupPolicyLatest CatchupPolicy = "latest"
+	// CatchupPolicyAll is the policy to execute all of the missed executions up to `Task.MaxCatchup`.
+	CatchupPolicyAll CatchupPolicy = "all"
+)
+
 // RetryLimit is the number to limit how many times to attempt retry.
 type RetryLimit int
 
 const (
 	// RetryLimitNever is the number not to attempt retry.
+	// This value is used by default.
 	RetryLimitNever RetryLimit = 0
 	// RetryLimitInfinite is the number to attempt retry without limit.
-	// This value is used by default.
 	RetryLimitInfinite RetryLimit = -1
 )
 
@@ -112,22 +288,93 @@ type Task struct {
 	// Description is a description of task.
 	Description string `json:"description" json:"description" toml:"description" yaml:"description"`
 	// Command is the executable name to exec.
-	Command string `validate:"required" json:"command" toml:"command" yaml:"command"`
+	// If `Shell` is true, it is the command line interpreted by `Interpreter`. (e.g. `du -sh /var/log/* | sort -h`)
+	Command string `validate:"required_without=Script,excluded_with=Script" json:"command" toml:"command" yaml:"command"`
 	// Args are the argument given for `Command`.
+	// If `Shell` is true or `Script` is specified, they are given as the positional parameters. (`$1`, `$2`, ...)
 	Args []string `json:"args" toml:"args" yaml:"args"`
+	// Shell is the option to execute `Command` with `Interpreter`.
+	Shell bool `json:"shell" toml:"shell" yaml:"shell"`
+	// Interpreter is the command line of the shell to interpret `Command` or `Script`. By default, use `/bin/sh -c`.
+	// (e.g. `bash -euo pipefail -c`)
+	// For `Script`, the trailing `-c` is removed and the path to the script file is given instead.
+	Interpreter string `json:"interpreter" toml:"interpreter" yaml:"interpreter"`
+	// Script is the inline script to be executed instead of `Command`.
+	// It is written into a temporary file and executed by `Interpreter`.
+	// If `UseTemplate` is true, the templates are applied to the script as well as `Args`.
+	Script string `json:"script" toml:"script" yaml:"script"`
 	// Schedule is the specification of the interval of task execution.
+	// It accepts 5 fields (Minutes, Hours, Day of month, Month, Day of week) like the standard cron,
+	// or 6 fields with Seconds at the beginning.
 	// [examples]
-	// `0 0 * * * *` (Every hour on the half hour) (Seconds, Minutes, Hours, Day of month, Month, Day of week)
-	// `@hourly` (Every hour)
-	// `@every 2h15m` (Every two hour fifteen
-	Schedule string `validate:"required" json:"schedule" toml:"schedule" yaml:"schedule"`
+	// `30 * * * *` (Every hour on the half hour)
+	// `0 30 * * * *` (Every hour on the half hour, with Seconds)
+	// `@hourly` (Every hour) `@daily`, `@weekly`, `@monthly` and `@yearly` are also available.
+	// `@every 2h15m` (Every two hours fifteen minutes)
+	// `@reboot` (Once when the worker started)
+	// `0 9 L * *` (At 09:00 on the last day of month) `L-2` means 2 days before the last day.
+	// `0 9 15W * *` (At 09:00 on the nearest weekday to the 15th) `LW` means the last weekday of month.
+	// `0 9 * * 2#2` (At 09:00 on the second Tuesday) `5L` means the last Friday.
+	// `0 H * * *` (Every hour at the minute derived from the name of the task)
+	// `H` is replaced with a stable value for each task to spread the tasks of the same schedule.
+	// `H(0-29)` limits the range of the value, and `H/15` means every 15 from a stable offset.
+	// `H` in day of month is limited to 1-28 unless the range is specified, so that the task is executed in every month.
+	// It can be omitted for the task executed only by the other tasks (See `DependsOn`), the API or `RunOnceAt`.
+	Schedule string `validate:"omitempty,schedule" json:"schedule" toml:"schedule" yaml:"schedule"`
+	// TimeZone is the time zone in which `Schedule` is interpreted. By default, use `Config.TimeZone`.
+	// It can be also specified by the prefix of `Schedule` such as `CRON_TZ=Asia/Tokyo 0 0 9 * * *`.
+	// On the transitions of daylight saving time, the times skipped by the transition fire at the transition,
+	// and the times repeated by the transition fire only once, unless the task is scheduled every hour.
+	TimeZone string `validate:"timezone|isdefault" json:"time_zone" toml:"time_zone" yaml:"time_zone"`
+	// Splay is the maximum seconds to delay each scheduled execution randomly.
+	// It spreads the tasks of the same schedule, but the time of the executions changes every time unlike `H`.
+	Splay int `validate:"gte=0" json:"splay" toml:"splay" yaml:"splay"`
+	// StartAt is the timestamp in RFC 3339 from which the task is scheduled. (e.g. `2024-12-01T00:00:00+09:00`)
+	// The task is shown as `pending` by the API before it.
+	StartAt string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" json:"start_at" toml:"start_at" yaml:"start_at"`
+	// EndAt is the timestamp in RFC 3339 until which the task is scheduled.
+	// The task is shown as `expired` by the API after it.
+	EndAt string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" json:"end_at" toml:"end_at" yaml:"end_at"`
+	// RunOnceAt is the timestamp in RFC 3339 to execute the task only once, instead of `Schedule`.
+	// The task is not executed if the worker is not running at the time.
+	// It cannot be combined with `StartAt` and `EndAt`, since the time itself is the active period.
+	RunOnceAt string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00,excluded_with=Schedule StartAt EndAt" json:"run_once_at" toml:"run_once_at" yaml:"run_once_at"`
+	// Catchup is the way to handle the executions missed while the worker was down.
+	// it must be one of `none`, `latest` and `all`. By default, use `none`.
+	// The missed executions are detected from the last scheduled execution in the history on startup,
+	// and recorded with the trigger `catchup` and the time when they were scheduled.
+	Catchup CatchupPolicy `validate:"oneof=none latest all|isdefault" json:"catchup" toml:"catchup" yaml:"catchup"`
+	// MaxCatchup is the maximum number of the missed executions to be executed with `all` policy.
+	// The latest ones are executed in chronological order. By default, 10.
+	MaxCatchup int `validate:"gte=0" json:"max_catchup" toml:"max_catchup" yaml:"max_catchup"`
+	// StartingDeadline is the seconds after the scheduled time until which the missed execution is caught up.
+	// The executions missed for longer than it are abandoned. By default, no deadline.
+	StartingDeadline int `validate:"gte=0" json:"starting_deadline" toml:"starting_deadline" yaml:"starting_deadline"`
+	// DependsOn is the names of the tasks on which the task depends. (e.g. `upload` depends on `compress`)
+	// The task is executed when all of them have succeeded since its last execution triggered by them,
+	// in addition to the executions by `Schedule`. Cycles of the dependencies are not allowed.
+	DependsOn []string `json:"depends_on" toml:"depends_on" yaml:"depends_on"`
+	// OnSuccess is the names of the tasks to be executed when the task succeeded.
+	OnSuccess []string `json:"on_success" toml:"on_success" yaml:"on_success"`
+	// OnFailure is the names of the tasks to be executed when the task failed after all attempts of retry.
+	OnFailure []string `json:"on_failure" toml:"on_failure" yaml:"on_failure"`
 	// UseTemplate is the option to enable template for `Args`.
 	// If true, the following templates are available on `Args`.
 	// `{{env "env_name"}}`: replaced with ENV["env_name"].
 	// `{{time "2006-01-02T15:04:05Z07:00"}}: replaced with the current time formed as `2020-01-01T00:00:00Z07:00`.
 	// see https://pkg.go.dev/time#pkg-constants for time format.
 	// `{{count}}`: replaced with the times of successful executions.
+	// `{{attempt}}`: replaced with the number of the attempt in a series of retry. It starts with 1.
 	UseTemplate bool `json:"use_template" toml:"use_template" yaml:"use_template"`
+	// WorkingDir is the working directory of the command. By default, use the one of Chronos worker.
+	WorkingDir string `json:"working_dir" toml:"working_dir" yaml:"working_dir"`
+	// User is the name or the ID of the user to execute the command. By default, use the one of Chronos worker.
+	// Chronos worker must be privileged to execute the command as another user.
+	User string `json:"user" toml:"user" yaml:"user"`
+	// Group is the name or the ID of the group to execute the command. By default, use the primary group of `User`.
+	Group string `json:"group" toml:"group" yaml:"group"`
+	// Umask is the umask of the command in octal. (e.g. `022`) By default, use the one of Chronos worker.
+	Umask string `validate:"omitempty,umask" json:"umask" toml:"umask" yaml:"umask"`
 	// Env is the environment variables which given for command.
 	Env map[string]string `json:"env" toml:"env" yaml:"env"`
 	// PropagateEnv is the switch to enable propagation of environment values.
@@ -135,17 +382,76 @@ type Task struct {
 	PropagateEnv bool `json:"propagate_env" toml:"propagate_env" yaml:"propagate_env"`
 	// Timeout is the seconds for timeout of command.
 	Timeout int `validate:"gte=0" json:"timeout" toml:"timeout" yaml:"timeout"`
-	// RetryLimit is the count of retry to be attempted. 0: infinite, -1:never retry
-	RetryLimit RetryLimit `validate:"gte=0" json:"retry_limit" toml:"retry_limit" yaml:"retry_limit"`
-	// RetryWait is the time to wait before retry in second.
-	RetryWait int `validate:"gt=0" json:"retry_wait" toml:"retry_wait" yaml:"retry_wait"`
-	// RetryType is the kind of retry. it must be one of `fixed` or `exponential`.
-	// (fixed: retry with fixed wait time, exponential: retry with exponential backoff)
-	RetryType RetryType `validate:"oneof=fixed exponential" json:"retry_type" toml:"retry_type" yaml:"retry_type"`
+	// KillSignal is the signal sent to the process group of the command on timeout or cancellation. By default, use `SIGTERM`.
+	// (e.g. `SIGINT`, `SIGTERM`, `SIGKILL`)
+	KillSignal string `validate:"omitempty,signal" json:"kill_signal" toml:"kill_signal" yaml:"kill_signal"`
+	// KillGracePeriod is the seconds to wait for the processes to exit after `KillSignal` is sent.
+	// The remaining processes are killed by `SIGKILL` after that. By default, 10 seconds.
+	KillGracePeriod int `validate:"gte=0" json:"kill_grace_period" toml:"kill_grace_period" yaml:"kill_grace_period"`
+	// RetryLimit is the count of retry to be attempted. 0: never retry (default), -1: infinite, N: retry up to N times
+	RetryLimit RetryLimit `validate:"gte=-1" json:"retry_limit" toml:"retry_limit" yaml:"retry_limit"`
+	// RetryWait is the time to wait before retry in second. By default, retry immediately.
+	RetryWait int `validate:"gte=0" json:"retry_wait" toml:"retry_wait" yaml:"retry_wait"`
+	// RetryDeadline is the seconds since the first attempt after which no more retry is attempted.
+	// By default, retry until `RetryLimit` is reached.
+	RetryDeadline int `validate:"gte=0" json:"retry_deadline" toml:"retry_deadline" yaml:"retry_deadline"`
+	// SuccessExitCodes are the exit codes regarded as success in addition to 0. (e.g. `3` for "nothing to do")
+	SuccessExitCodes []int `json:"success_exit_codes" toml:"success_exit_codes" yaml:"success_exit_codes"`
+	// RetryOnExitCodes are the exit codes to be retried. (e.g. `75` for "temporary failure")
+	// If `RetryOnExitCodes` or `RetryOnStderr` is specified, only the failures which match either of them are retried.
+	// Otherwise, all failures are retried.
+	RetryOnExitCodes []int `json:"retry_on_exit_codes" toml:"retry_on_exit_codes" yaml:"retry_on_exit_codes"`
+	// NoRetryExitCodes are the exit codes never to be retried.
+	NoRetryExitCodes []int `json:"no_retry_exit_codes" toml:"no_retry_exit_codes" yaml:"no_retry_exit_codes"`
+	// RetryOnStderr is the regular expression matched with STDERR of the failures to be retried.
+	// (e.g. `connection refused|timed out`)
+	RetryOnStderr string `validate:"omitempty,regexp" json:"retry_on_stderr" toml:"retry_on_stderr" yaml:"retry_on_stderr"`
+	// RetryType is the kind of retry. it must be one of `fixed`, `linear` or `exponential`. By default, use `fixed`.
+	// (fixed: retry with fixed wait time, linear: retry with linear backoff, exponential: retry with exponential backoff)
+	RetryType RetryType `validate:"oneof=fixed linear exponential|isdefault" json:"retry_type" toml:"retry_type" yaml:"retry_type"`
+	// RetryMaxWait is the maximum seconds to wait before retry. By default, the wait is not capped.
+	RetryMaxWait int `validate:"gte=0" json:"retry_max_wait" toml:"retry_max_wait" yaml:"retry_max_wait"`
+	// RetryJitter is the kind of randomization of the wait before retry. it must be one of `full`, `equal` and `decorrelated`.
+	// By default, the wait is not randomized.
+	// (full: random between 0 and the wait, equal: random between the half of the wait and the wait,
+	// decorrelated: random between `RetryWait` and 3 times the previous wait regardless of `RetryType`)
+	RetryJitter RetryJitter `validate:"oneof=full equal decorrelated|isdefault" json:"retry_jitter" toml:"retry_jitter" yaml:"retry_jitter"`
 	// Fallthrough is the flag to ignore the failure of command entirely.
 	// `FailureCount` will be ignored with this enabled this option.
 	Fallthrough bool `json:"fallthrough" toml:"fallthrough" toml:"fallthrough" yaml:"fallthrough"`
 	// FailureCount is the number of failure which makes HealthCheck failed.
-	// If the command failed `FailureCount` times or more, HealthCheck for the task shows failing status.
+	// If the command failed `FailureCount` times or more in a row, HealthCheck for the task shows failing status.
+	// A series of retry is counted as one failure. By default, a single failure makes HealthCheck failed.
 	FailureCount int `validate:"gte=0" json:"failure_count" toml:"failure_count" yaml:"failure_count"`
+	// SuccessThreshold is the number of consecutive successes which makes the failing HealthCheck healthy again.
+	// By default, a single success makes it healthy.
+	SuccessThreshold int `validate:"gte=0" json:"success_threshold" toml:"success_threshold" yaml:"success_threshold"`
+	// FailureWindow is the number of the latest runs in which failures are counted.
+	// If specified, HealthCheck shows failing status when `FailureCount` of the last `FailureWindow` runs failed
+	// even if they are not in a row. (e.g. `failure_count: 3` and `failure_window: 10` for "3 of last 10 runs failed")
+	FailureWindow int `validate:"omitempty,gtefield=FailureCount" json:"failure_window" toml:"failure_window" yaml:"failure_window"`
+	// MaxStaleness is the seconds within which the task is expected to succeed.
+	// If specified, HealthCheck shows failing status when no successful execution has been recorded in `MaxStaleness` seconds.
+	MaxStaleness int `validate:"gte=0" json:"max_staleness" toml:"max_staleness" yaml:"max_staleness"`
+	// DetectStaleness is the option to derive the staleness from `Schedule`.
+	// If true, HealthCheck shows failing status when no successful execution has been recorded
+	// since the time when the task was expected to be executed plus `Splay` and `StalenessGrace`.
+	DetectStaleness bool `json:"detect_staleness" toml:"detect_staleness" yaml:"detect_staleness"`
+	// StalenessGrace is the seconds of grace period for `DetectStaleness`. By default, 60 seconds.
+	// It should be longer than the time taken for the task including retries.
+	StalenessGrace int `validate:"gte=0" json:"staleness_grace" toml:"staleness_grace" yaml:"staleness_grace"`
+	// ConcurrencyPolicy is the way to handle a new execution while the previous one is still running.
+	// it must be one of `allow`, `forbid` and `replace`. By default, use `allow`.
+	// (allow: run concurrently, forbid: skip the new one, replace: cancel the running one and start the new one)
+	ConcurrencyPolicy ConcurrencyPolicy `validate:"oneof=allow forbid replace|isdefault" json:"concurrency_policy" toml:"concurrency_policy" yaml:"concurrency_policy"`
+	// Notification is the settings for notifications of the results of the task.
+	// If specified, it is used instead of the global one.
+	Notification *Notification `json:"notification" toml:"notification" yaml:"notification"`
+	// HistoryLimit is the number of executions to be kept in the history. By default, keep 100 executions.
+	HistoryLimit int `validate:"gte=0" json:"history_limit" toml:"history_limit" yaml:"history_limit"`
+	// HistoryMaxAge is the seconds to keep executions in the history. By default, executions are kept regardless of their age.
+	HistoryMaxAge int `validate:"gte=0" json:"history_max_age" toml:"history_max_age" yaml:"history_max_age"`
+	// Exclude is the periods in which the executions of the task are skipped.
+	// It is applied in addition to the global one.
+	Exclude *Exclusion `json:"exclude" toml:"exclude" yaml:"exclude"`
 }
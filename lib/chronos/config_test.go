@@ -25,7 +25,7 @@ const (
 				"fuga"
 			],
                         "use_template": true,
-			"schedule": "0 0 0 0 0",
+			"schedule": "0 0 1 1 *",
 			"env": {
 				"TZ": "Asia/Tokyo"
 			},
@@ -53,7 +53,7 @@ tasks:
       - hoge
       - fuga
     use_template: true
-    schedule: 0 0 0 0 0
+    schedule: 0 0 1 1 *
     timeout: 30
     fallthrough: true
     retry_limit: 3
@@ -76,7 +76,7 @@ port = 30001
 description = "hello"
 command = "echo"
 args = [ "hoge", "fuga" ]
-schedule = "0 0 0 0 0"
+schedule = "0 0 1 1 *"
 use_template = true
 timeout = 30
 fallthrough = true
@@ -105,7 +105,7 @@ var fixtureConfig = &chronos.Config{
 				"fuga",
 			},
 			UseTemplate:  true,
-			Schedule:     "0 0 0 0 0",
+			Schedule:     "0 0 1 1 *",
 			Timeout:      30,
 			RetryLimit:   3,
 			RetryWait:    30,
@@ -163,3 +163,55 @@ func TestNewConfigWithTOML(t *testing.T) {
 		t.Errorf("parsed config differs from the one expected: %s", diff)
 	}
 }
+
+func TestNewConfigValidateTasks(t *testing.T) {
+	tests := []struct {
+		name  string
+		task  string
+		field string
+	}{
+		{name: "command", task: `{"schedule": "@hourly"}`, field: "Config.tasks[hello].command"},
+		{name: "schedule", task: `{"command": "echo", "schedule": "@invalid"}`, field: "Config.tasks[hello].schedule"},
+		{name: "timeout", task: `{"command": "echo", "timeout": -1}`, field: "Config.tasks[hello].timeout"},
+		{name: "retry_limit", task: `{"command": "echo", "retry_limit": -2}`, field: "Config.tasks[hello].retry_limit"},
+		{name: "retry_wait", task: `{"command": "echo", "retry_wait": -1}`, field: "Config.tasks[hello].retry_wait"},
+		{name: "retry_type", task: `{"command": "echo", "retry_type": "random"}`, field: "Config.tasks[hello].retry_type"},
+		{name: "retry_on_stderr", task: `{"command": "echo", "retry_on_stderr": "("}`, field: "Config.tasks[hello].retry_on_stderr"},
+		{name: "kill_signal", task: `{"command": "echo", "kill_signal": "SIGFOO"}`, field: "Config.tasks[hello].kill_signal"},
+		{name: "umask", task: `{"command": "echo", "umask": "999"}`, field: "Config.tasks[hello].umask"},
+		{name: "failure_count", task: `{"command": "echo", "failure_count": -1}`, field: "Config.tasks[hello].failure_count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := strings.NewReader(`{"tasks": {"hello": ` + tt.task + `}}`)
+			_, err := chronos.NewConfig(r, "test.json")
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("the error must be reported on %s. got: %s", tt.field, err)
+			}
+		})
+	}
+
+	r := strings.NewReader(`{"tasks": {"hello": {"command": "echo"}}}`)
+	if _, err := chronos.NewConfig(r, "test.json"); err != nil {
+		t.Errorf("the task with the default values must be valid. got: %s", err)
+	}
+}
+
+func TestNewConfigRunOnceAt(t *testing.T) {
+	for _, field := range []string{"schedule", "start_at", "end_at"} {
+		t.Run(field, func(t *testing.T) {
+			value := `"2099-01-01T00:00:00Z"`
+			if field == "schedule" {
+				value = `"@daily"`
+			}
+			r := strings.NewReader(`{"tasks": {"hello": {"command": "echo", "run_once_at": "2099-06-01T00:00:00Z", "` + field + `": ` + value + `}}}`)
+			_, err := chronos.NewConfig(r, "test.json")
+			if err == nil || !strings.Contains(err.Error(), "Config.tasks[hello].run_once_at") {
+				t.Errorf("`run_once_at` must not be combined with `%s`. got: %v", field, err)
+			}
+		})
+	}
+}
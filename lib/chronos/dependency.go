@@ -0,0 +1,233 @@
+package chronos
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyCondition is the enum of the results of the upstream task which trigger the downstream one.
+type DependencyCondition string
+
+const (
+	// DependencyConditionSuccess is the condition to trigger the downstream task when the upstream one succeeded.
+	DependencyConditionSuccess DependencyCondition = "success"
+	// DependencyConditionFailure is the condition to trigger the downstream task when the upstream one failed.
+	DependencyConditionFailure DependencyCondition = "failure"
+)
+
+// Dependency is an edge of the DAG of tasks.
+type Dependency struct {
+	// Name is the name of the task on the other side of the edge.
+	Name string `json:"name"`
+	// Condition is the result of the upstream task to trigger the downstream one.
+	Condition DependencyCondition `json:"condition"`
+}
+
+// DependencyError is the error of malformed dependencies between tasks.
+type DependencyError struct {
+	// Field is the path to the field which has the error. (e.g. `tasks.upload.depends_on`)
+	Field string
+	// Message is the description of the error.
+	Message string
+}
+
+func (e *DependencyError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// dependencyEdge is an edge from the upstream task to the downstream one.
+type dependencyEdge struct {
+	from, to  string
+	condition DependencyCondition
+	// join is true if the edge comes from `DependsOn`, which waits for all of the upstream tasks.
+	join bool
+}
+
+// dependencyEdges returns the edges between tasks defined by `DependsOn`, `OnSuccess` and `OnFailure`.
+// The edges are sorted by the upstream, the downstream and the condition.
+func dependencyEdges(tasks map[string]*Task) []dependencyEdge {
+	type key struct {
+		from, to  string
+		condition DependencyCondition
+	}
+	edges := make(map[key]bool)
+	add := func(from, to string, condition DependencyCondition, join bool) {
+		k := key{from: from, to: to, condition: condition}
+		edges[k] = edges[k] || join
+	}
+	for name, t := range tasks {
+		for _, upstream := range t.DependsOn {
+			add(upstream, name, DependencyConditionSuccess, true)
+		}
+		for _, downstream := range t.OnSuccess {
+			add(name, downstream, DependencyConditionSuccess, false)
+		}
+		for _, downstream := range t.OnFailure {
+			add(name, downstream, DependencyConditionFailure, false)
+		}
+	}
+
+	ret := make([]dependencyEdge, 0, len(edges))
+	for k, join := range edges {
+		ret = append(ret, dependencyEdge{from: k.from, to: k.to, condition: k.condition, join: join})
+	}
+	sort.Slice(ret, func(a, b int) bool {
+		if ret[a].from != ret[b].from {
+			return ret[a].from < ret[b].from
+		}
+		if ret[a].to != ret[b].to {
+			return ret[a].to < ret[b].to
+		}
+		return ret[a].condition < ret[b].condition
+	})
+	return ret
+}
+
+// validateDependencies returns `DependencyError` if the tasks refer to unknown tasks or their dependencies have a cycle.
+func validateDependencies(tasks map[string]*Task) error {
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t := tasks[name]
+		fields := []struct {
+			name string
+			refs []string
+		}{
+			{"depends_on", t.DependsOn},
+			{"on_success", t.OnSuccess},
+			{"on_failure", t.OnFailure},
+		}
+		for _, f := range fields {
+			for _, ref := range f.refs {
+				if _, ok := tasks[ref]; !ok {
+					return &DependencyError{
+						Field:   fmt.Sprintf("tasks.%s.%s", name, f.name),
+						Message: fmt.Sprintf("Task `%s` is not found", ref),
+					}
+				}
+			}
+		}
+	}
+
+	downstream := make(map[string][]string)
+	for _, e := range dependencyEdges(tasks) {
+		downstream[e.from] = append(downstream[e.from], e.to)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(tasks))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return &DependencyError{
+				Field:   fmt.Sprintf("tasks.%s", name),
+				Message: fmt.Sprintf("dependencies have a cycle: %s", strings.Join(cycle, " -> ")),
+			}
+		case visited:
+			return nil
+		}
+		marks[name] = visiting
+		path = append(path, name)
+		for _, next := range downstream[name] {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// satisfy marks `upstream` in `DependsOn` as succeeded.
+// It returns true and resets the marks when all of the upstream tasks have succeeded.
+func (j *Job) satisfy(upstream string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.satisfied == nil {
+		j.satisfied = make(map[string]bool)
+	}
+	j.satisfied[upstream] = true
+	for _, name := range j.task.DependsOn {
+		if !j.satisfied[name] {
+			return false
+		}
+	}
+	j.satisfied = nil
+	return true
+}
+
+// finish notifies the completion of a run of the Job to trigger the downstream tasks.
+func (j *Job) finish(succeeded bool) {
+	if j.onFinish != nil {
+		j.onFinish(j, succeeded)
+	}
+}
+
+// dependencies returns the upstream and downstream edges of the task in the current config.
+func (w *Worker) dependencies(name string) (upstream []*Dependency, downstream []*Dependency) {
+	for _, e := range dependencyEdges(w.config().Tasks) {
+		if e.to == name {
+			upstream = append(upstream, &Dependency{Name: e.from, Condition: e.condition})
+		}
+		if e.from == name {
+			downstream = append(downstream, &Dependency{Name: e.to, Condition: e.condition})
+		}
+	}
+	return upstream, downstream
+}
+
+// triggerDownstream executes the downstream tasks of the Job according to the result of its run.
+// The tasks in `DependsOn` of the downstream task must have all succeeded to trigger it.
+func (w *Worker) triggerDownstream(upstream *Job, succeeded bool) {
+	condition := DependencyConditionFailure
+	if succeeded {
+		condition = DependencyConditionSuccess
+	}
+
+	for _, e := range dependencyEdges(w.config().Tasks) {
+		if e.from != upstream.name || e.condition != condition {
+			continue
+		}
+		j := w.Job(e.to)
+		if j == nil {
+			continue
+		}
+		if e.join && !j.satisfy(upstream.name) {
+			w.logger.Debugf("Task `%s` is waiting for the other tasks in `depends_on`.", j.name)
+			continue
+		}
+
+		ctx, ok := w.startRun()
+		if !ok {
+			return
+		}
+		w.logger.Infof("Task `%s` was triggered by the %s of Task `%s`.", j.name, condition, upstream.name)
+		go func() {
+			defer w.runs.Done()
+			j.runUnlessPaused(ctx, runCause{trigger: TriggerDependency, triggeredBy: upstream.name})
+		}()
+	}
+}
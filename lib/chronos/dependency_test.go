@@ -0,0 +1,169 @@
+package chronos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks map[string]*Task
+		want  *DependencyError
+	}{
+		{
+			name: "chain",
+			tasks: map[string]*Task{
+				"dump":     {OnSuccess: []string{"compress"}, OnFailure: []string{"alert"}},
+				"compress": {},
+				"upload":   {DependsOn: []string{"compress", "dump"}},
+				"alert":    {},
+			},
+		},
+		{
+			name: "unknown task",
+			tasks: map[string]*Task{
+				"upload": {DependsOn: []string{"compress"}},
+			},
+			want: &DependencyError{Field: "tasks.upload.depends_on", Message: "Task `compress` is not found"},
+		},
+		{
+			name: "self",
+			tasks: map[string]*Task{
+				"loop": {OnFailure: []string{"loop"}},
+			},
+			want: &DependencyError{Field: "tasks.loop", Message: "dependencies have a cycle: loop -> loop"},
+		},
+		{
+			name: "cycle",
+			tasks: map[string]*Task{
+				"a": {},
+				"b": {DependsOn: []string{"a"}},
+				"c": {DependsOn: []string{"b"}, OnSuccess: []string{"d"}},
+				"d": {OnFailure: []string{"a"}},
+			},
+			want: &DependencyError{Field: "tasks.a", Message: "dependencies have a cycle: a -> b -> c -> d -> a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDependencies(tt.tasks)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+			got, ok := err.(*DependencyError)
+			if !ok {
+				t.Fatalf("expected DependencyError. got: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJobSatisfy(t *testing.T) {
+	j := NewJob("upload", &Task{DependsOn: []string{"compress", "checksum"}}, NewMemoryHistoryStore(), &logger.NopLogger{})
+
+	if j.satisfy("compress") {
+		t.Errorf("must wait for `checksum`")
+	}
+	if j.satisfy("compress") {
+		t.Errorf("must wait for `checksum` even if `compress` succeeded twice")
+	}
+	if !j.satisfy("checksum") {
+		t.Errorf("must be satisfied by all of the upstream tasks")
+	}
+	if j.satisfy("checksum") {
+		t.Errorf("must wait for `compress` again after triggered")
+	}
+}
+
+func TestWorkerTriggerDownstream(t *testing.T) {
+	conf := &Config{
+		History: &History{Driver: HistoryDriverMemory},
+		Tasks: map[string]*Task{
+			"dump":     {Command: "true", Schedule: "@yearly", OnFailure: []string{"alert"}},
+			"compress": {Command: "true", DependsOn: []string{"dump"}},
+			"upload":   {Command: "false", DependsOn: []string{"compress"}, OnFailure: []string{"alert"}},
+			"alert":    {Command: "true"},
+		},
+	}
+	w, err := NewWorker(conf, &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create worker: %s", err)
+	}
+
+	w.Job("dump").Trigger(context.Background())
+
+	var executions []*Execution
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		executions, err = w.Job("alert").History(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(executions) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(executions) != 1 {
+		t.Fatalf("`alert` must be triggered once by the failure of `upload`. got: %d", len(executions))
+	}
+	if e := executions[0]; e.Trigger != TriggerDependency || e.TriggeredBy != "upload" {
+		t.Errorf("unexpected execution of `alert`: %+v", e)
+	}
+	for _, name := range []string{"compress", "upload"} {
+		executions, err := w.Job(name).History(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(executions) != 1 || executions[0].Trigger != TriggerDependency {
+			t.Errorf("`%s` must be triggered once by its dependency. got: %+v", name, executions)
+		}
+	}
+
+	upstream, downstream := w.dependencies("upload")
+	if diff := cmp.Diff([]*Dependency{{Name: "compress", Condition: DependencyConditionSuccess}}, upstream); diff != "" {
+		t.Errorf("unexpected upstream (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]*Dependency{{Name: "alert", Condition: DependencyConditionFailure}}, downstream); diff != "" {
+		t.Errorf("unexpected downstream (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkerReloadKeepsSatisfied(t *testing.T) {
+	newConfig := func(args ...string) *Config {
+		return &Config{
+			History: &History{Driver: HistoryDriverMemory},
+			Tasks: map[string]*Task{
+				"dump":     {Command: "true"},
+				"compress": {Command: "true"},
+				"upload":   {Command: "true", Args: args, DependsOn: []string{"dump", "compress"}},
+			},
+		}
+	}
+	w, err := NewWorker(newConfig(), &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create worker: %s", err)
+	}
+	if w.Job("upload").satisfy("dump") {
+		t.Fatal("`upload` must wait for `compress`")
+	}
+
+	if err := w.Reload(newConfig("changed")); err != nil {
+		t.Fatalf("failed to reload config: %s", err)
+	}
+	if !w.Job("upload").satisfy("compress") {
+		t.Errorf("the success of `dump` before reload must be taken over")
+	}
+}
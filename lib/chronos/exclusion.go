@@ -0,0 +1,139 @@
+package chronos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseExclusionSpec parses the cron expression of `Exclusion.Schedules`.
+// Unlike `Task.Schedule`, the expression in 5 fields matches every second in the minutes,
+// and the descriptors are not available.
+func parseExclusionSpec(spec string) (*specSchedule, error) {
+	if strings.HasPrefix(strings.TrimSpace(spec), "@") {
+		return nil, fmt.Errorf("descriptors are not available for exclusion: %s", spec)
+	}
+	if len(strings.Fields(spec)) == 5 {
+		spec = "* " + spec
+	}
+	s, err := parseCronSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return s.(*specSchedule), nil
+}
+
+// match returns true if `t` matches all fields of the schedule.
+func (s *specSchedule) match(t time.Time) bool {
+	return s.second&(1<<uint(t.Second())) != 0 &&
+		s.minute&(1<<uint(t.Minute())) != 0 &&
+		s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.month&(1<<uint(t.Month())) != 0 &&
+		s.matchDay(t)
+}
+
+// period returns the period of the range.
+func (r *ExclusionRange) period() (start time.Time, end time.Time, err error) {
+	if start, err = parseTimestamp(r.Start); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end, err = parseTimestamp(r.End); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("`end` is before `start`: %s < %s", r.End, r.Start)
+	}
+	return start, end, nil
+}
+
+// excluded returns the description of the exclusion which `t` falls in, or empty string if `t` is not excluded.
+// `Schedules` and the floating times in `Calendars` are evaluated in the time zone of `t`.
+// It returns error with the description if any exclusion cannot be evaluated.
+func (e *Exclusion) excluded(t time.Time) (string, error) {
+	var errs []string
+	for _, r := range e.Ranges {
+		start, end, err := r.period()
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if !t.Before(start) && t.Before(end) {
+			return fmt.Sprintf("range %s - %s", r.Start, r.End), nil
+		}
+	}
+	for _, spec := range e.Schedules {
+		s, err := parseExclusionSpec(spec)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if s.match(t) {
+			return fmt.Sprintf("schedule `%s`", spec), nil
+		}
+	}
+	for _, filename := range e.Calendars {
+		events, _, err := loadCalendar(filename, t.Location())
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		for _, event := range events {
+			if event.contains(t) {
+				return fmt.Sprintf("event `%s` in %s", event.summary, filename), nil
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return "", fmt.Errorf("failed to evaluate exclusions: %s", strings.Join(errs, ", "))
+	}
+	return "", nil
+}
+
+// exclusion returns the description of the exclusion which the current time falls in,
+// or empty string if the Job is not excluded now.
+// The Job is regarded as excluded while any exclusion cannot be evaluated. (e.g. the calendar is missing)
+func (j *Job) exclusion() string {
+	if j.excluded == nil {
+		return ""
+	}
+	reason, err := j.excluded(j, time.Now())
+	if reason == "" && err != nil {
+		// the execution is skipped rather than executed during the exclusions by mistake
+		j.logger.Errorf("Task `%s` %s", j.name, err)
+		return fmt.Sprintf("exclusions which cannot be evaluated (%s)", err)
+	}
+	return reason
+}
+
+// excluded returns the description of the global or task exclusion which `now` falls in.
+// The exclusions are evaluated in the time zone of the task.
+func (w *Worker) excluded(j *Job, now time.Time) (string, error) {
+	w.mu.RLock()
+	conf, loc := w.conf, w.loc
+	w.mu.RUnlock()
+	if conf.Exclude == nil && j.task.Exclude == nil {
+		return "", nil
+	}
+	if zone, _, err := j.task.zone(loc); err == nil {
+		loc = zone
+	}
+
+	now = now.In(loc)
+	var errs []string
+	for _, e := range []*Exclusion{conf.Exclude, j.task.Exclude} {
+		if e == nil {
+			continue
+		}
+		reason, err := e.excluded(now)
+		if reason != "" {
+			return reason, nil
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return "", fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return "", nil
+}
@@ -0,0 +1,115 @@
+package chronos
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestExclusionExcluded(t *testing.T) {
+	calendar := filepath.Join(t.TempDir(), "holidays.ics")
+	err := os.WriteFile(calendar, []byte(testCalendar), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Exclusion{
+		Ranges:    []*ExclusionRange{{Start: "2024-06-01T00:00:00Z", End: "2024-06-01T06:00:00Z"}},
+		Schedules: []string{"* 2-4 * * SAT,SUN"},
+		Calendars: []string{calendar},
+	}
+
+	tests := []struct {
+		time time.Time
+		want string
+	}{
+		{time: time.Date(2024, 6, 1, 5, 59, 59, 0, time.UTC), want: "range 2024-06-01T00:00:00Z - 2024-06-01T06:00:00Z"},
+		{time: time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC), want: ""},
+		// Sunday
+		{time: time.Date(2024, 6, 2, 4, 59, 30, 0, time.UTC), want: "schedule `* 2-4 * * SAT,SUN`"},
+		{time: time.Date(2024, 6, 3, 3, 0, 0, 0, time.UTC), want: ""},
+		{time: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), want: "event `New Year's Day` in " + calendar},
+	}
+	for _, tt := range tests {
+		got, err := e.excluded(tt.time)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != tt.want {
+			t.Errorf("unexpected exclusion at %s. want: %q, got: %q", tt.time, tt.want, got)
+		}
+	}
+
+	e.Calendars = append(e.Calendars, filepath.Join(t.TempDir(), "missing.ics"))
+	if _, err := e.excluded(time.Date(2024, 6, 3, 3, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("expected error for the missing calendar")
+	}
+}
+
+func TestParseExclusionSpec(t *testing.T) {
+	for _, spec := range []string{"@daily", "@every 1h", "* * *", "* 25 * * *"} {
+		if _, err := parseExclusionSpec(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestWorkerExclusion(t *testing.T) {
+	conf := &Config{
+		History: &History{Driver: HistoryDriverMemory},
+		Exclude: &Exclusion{Schedules: []string{"* * * * * *"}},
+		Tasks: map[string]*Task{
+			"excluded": {Command: "true", Schedule: "@yearly"},
+		},
+	}
+	w, err := NewWorker(conf, &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create worker: %s", err)
+	}
+	j := w.Job("excluded")
+
+	j.RunContext(context.Background())
+	executions, err := j.History(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(executions) != 1 || executions[0].Status != ExecutionStatusSkipped || !strings.HasPrefix(executions[0].Error, "excluded by schedule") {
+		t.Fatalf("the scheduled execution must be skipped. got: %+v", executions)
+	}
+
+	// the manual execution is not excluded
+	if e := j.Trigger(context.Background()); e.Status != ExecutionStatusSucceeded {
+		t.Errorf("the manual execution must not be skipped. got: %+v", e)
+	}
+}
+
+func TestWorkerExclusionMissingCalendar(t *testing.T) {
+	conf := &Config{
+		History: &History{Driver: HistoryDriverMemory},
+		Tasks: map[string]*Task{
+			"excluded": {
+				Command:  "true",
+				Schedule: "@yearly",
+				Exclude:  &Exclusion{Calendars: []string{filepath.Join(t.TempDir(), "missing.ics")}},
+			},
+		},
+	}
+	w, err := NewWorker(conf, &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create worker: %s", err)
+	}
+	j := w.Job("excluded")
+
+	j.RunContext(context.Background())
+	executions, err := j.History(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(executions) != 1 || executions[0].Status != ExecutionStatusSkipped || !strings.HasPrefix(executions[0].Error, "excluded by exclusions which cannot be evaluated") {
+		t.Fatalf("the execution must be skipped while the calendar cannot be read. got: %+v", executions)
+	}
+}
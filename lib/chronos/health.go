@@ -0,0 +1,128 @@
+package chronos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// health tracks the results of the recent runs of a Job to determine its `State`.
+// A run is a series of retry, and it is counted once regardless of the number of attempts.
+type health struct {
+	// results are the results of the recent runs in chronological order. true means the run failed.
+	// It is kept only when `Task.FailureWindow` is specified.
+	results              []bool
+	consecutiveFailures  int
+	consecutiveSuccesses int
+	// reason is the reason why the Job is unhealthy.
+	reason string
+}
+
+// failureThreshold returns the number of failed runs which makes the task unhealthy.
+func (t *Task) failureThreshold() int {
+	if t.FailureCount > 0 {
+		return t.FailureCount
+	}
+	return 1
+}
+
+// successThreshold returns the number of consecutive successful runs which makes the unhealthy task healthy.
+func (t *Task) successThreshold() int {
+	if t.SuccessThreshold > 0 {
+		return t.SuccessThreshold
+	}
+	return 1
+}
+
+// observe updates the health with the result of a run and returns the new state.
+func (h *health) observe(task *Task, state State, failed bool, e *Execution) State {
+	if window := task.FailureWindow; window > 0 {
+		h.results = append(h.results, failed)
+		if len(h.results) > window {
+			h.results = h.results[len(h.results)-window:]
+		}
+	}
+
+	if !failed {
+		h.consecutiveFailures = 0
+		h.consecutiveSuccesses++
+		if state == StateUnhealthy && h.consecutiveSuccesses < task.successThreshold() {
+			return StateUnhealthy
+		}
+		if state == StateUnhealthy {
+			// forget the failures before the recovery not to make the task unhealthy again immediately
+			h.results = nil
+		}
+		h.reason = ""
+		return StateHealthy
+	}
+
+	h.consecutiveSuccesses = 0
+	h.consecutiveFailures++
+	threshold := task.failureThreshold()
+	switch {
+	case task.FailureWindow > 0:
+		failures := 0
+		for _, f := range h.results {
+			if f {
+				failures++
+			}
+		}
+		if failures < threshold {
+			return state
+		}
+		h.reason = fmt.Sprintf("%d of the last %d runs failed: %s", failures, len(h.results), e.Error)
+	case h.consecutiveFailures >= threshold:
+		h.reason = fmt.Sprintf("%d consecutive runs failed: %s", h.consecutiveFailures, e.Error)
+	default:
+		return state
+	}
+	return StateUnhealthy
+}
+
+// DefaultStalenessGrace is the seconds of grace period for `Task.DetectStaleness` when `Task.StalenessGrace` is not specified.
+const DefaultStalenessGrace = 60
+
+// staleness returns the reason why the Job is stale at `now`, or empty string if it is not stale.
+// The staleness is measured from the last success, or the time when the Job was created if it is later.
+// `schedule` is used to derive the time when the Job is expected to succeed for `Task.DetectStaleness`.
+// The paused Job and the Job out of its active period are never stale.
+func (j *Job) staleness(now time.Time, schedule cron.Schedule) string {
+	j.mu.RLock()
+	lastSuccess, since, paused := j.lastSuccess, j.since, j.paused
+	j.mu.RUnlock()
+	if paused || j.task.windowState(now) != WindowStateActive {
+		return ""
+	}
+
+	from := since
+	if lastSuccess.After(from) {
+		from = lastSuccess
+	}
+	describe := func() string {
+		if lastSuccess.IsZero() {
+			return "no successful execution has been recorded"
+		}
+		return fmt.Sprintf("the last success was at %s", lastSuccess.Format(time.RFC3339))
+	}
+
+	if maxStaleness := time.Duration(j.task.MaxStaleness) * time.Second; maxStaleness > 0 {
+		if now.Sub(from) > maxStaleness {
+			return fmt.Sprintf("stale for more than %d seconds: %s", j.task.MaxStaleness, describe())
+		}
+	}
+	if j.task.DetectStaleness && schedule != nil {
+		grace := time.Duration(j.task.StalenessGrace) * time.Second
+		if grace == 0 {
+			grace = DefaultStalenessGrace * time.Second
+		}
+		// the execution may be delayed up to `Splay`
+		grace += time.Duration(j.task.Splay) * time.Second
+		expected := schedule.Next(from)
+		if !expected.IsZero() && now.After(expected.Add(grace)) {
+			return fmt.Sprintf("expected to succeed at %s: %s", expected.Format(time.RFC3339), describe())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package chronos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/robfig/cron"
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestHealthObserve(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Task
+		// results are the results of runs. true means the run failed.
+		results []bool
+		want    []State
+	}{
+		{
+			name:    "default",
+			task:    &Task{},
+			results: []bool{false, true, true, false},
+			want:    []State{StateHealthy, StateUnhealthy, StateUnhealthy, StateHealthy},
+		},
+		{
+			name:    "consecutive failures",
+			task:    &Task{FailureCount: 3},
+			results: []bool{true, true, false, true, true, true},
+			want:    []State{StateHealthy, StateHealthy, StateHealthy, StateHealthy, StateHealthy, StateUnhealthy},
+		},
+		{
+			name:    "success threshold",
+			task:    &Task{SuccessThreshold: 2},
+			results: []bool{true, false, true, false, false},
+			want:    []State{StateUnhealthy, StateUnhealthy, StateUnhealthy, StateUnhealthy, StateHealthy},
+		},
+		{
+			name:    "sliding window",
+			task:    &Task{FailureCount: 2, FailureWindow: 3},
+			results: []bool{true, false, false, true, false, true, false},
+			want:    []State{StateHealthy, StateHealthy, StateHealthy, StateHealthy, StateHealthy, StateUnhealthy, StateHealthy},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &health{}
+			state := State(StateHealthy)
+			var got []State
+			for _, failed := range tt.results {
+				state = h.observe(tt.task, state, failed, &Execution{Error: "exit status 1"})
+				got = append(got, state)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected states (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJobHealthReason(t *testing.T) {
+	task := &Task{
+		Command:      "sh",
+		Args:         []string{"-c", "exit 1"},
+		RetryType:    RetryTypeFixed,
+		FailureCount: 2,
+	}
+	j := NewJob("fail", task, NewMemoryHistoryStore(), &logger.NopLogger{})
+
+	j.Run()
+	if !j.IsHealthy() || j.HealthReason() != "" {
+		t.Fatalf("Job must be healthy after a single failure. reason: %s", j.HealthReason())
+	}
+	j.Run()
+	if j.IsHealthy() {
+		t.Fatalf("Job must be unhealthy after consecutive failures")
+	}
+	if got, want := j.HealthReason(), "2 consecutive runs failed: exit status 1"; got != want {
+		t.Errorf("unexpected reason. got: %s, want: %s", got, want)
+	}
+}
+
+func TestJobStaleness(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule, err := cron.Parse("0 0 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		task        *Task
+		lastSuccess time.Time
+		now         time.Time
+		stale       bool
+	}{
+		{
+			name:  "disabled",
+			task:  &Task{},
+			now:   since.Add(24 * time.Hour),
+			stale: false,
+		},
+		{
+			name:  "within max staleness",
+			task:  &Task{MaxStaleness: 3600},
+			now:   since.Add(30 * time.Minute),
+			stale: false,
+		},
+		{
+			name:  "exceeded max staleness since creation",
+			task:  &Task{MaxStaleness: 3600},
+			now:   since.Add(90 * time.Minute),
+			stale: true,
+		},
+		{
+			name:        "measured from the last success",
+			task:        &Task{MaxStaleness: 3600},
+			lastSuccess: since.Add(60 * time.Minute),
+			now:         since.Add(90 * time.Minute),
+			stale:       false,
+		},
+		{
+			name:  "within grace period of the schedule",
+			task:  &Task{DetectStaleness: true},
+			now:   since.Add(time.Hour + 30*time.Second),
+			stale: false,
+		},
+		{
+			name:  "exceeded grace period of the schedule",
+			task:  &Task{DetectStaleness: true, StalenessGrace: 10},
+			now:   since.Add(time.Hour + 30*time.Second),
+			stale: true,
+		},
+		{
+			name:  "within splay of the schedule",
+			task:  &Task{DetectStaleness: true, StalenessGrace: 10, Splay: 60},
+			now:   since.Add(time.Hour + 30*time.Second),
+			stale: false,
+		},
+		{
+			name:  "exceeded splay and grace period of the schedule",
+			task:  &Task{DetectStaleness: true, StalenessGrace: 10, Splay: 60},
+			now:   since.Add(time.Hour + 90*time.Second),
+			stale: true,
+		},
+		{
+			name:        "succeeded at the expected time",
+			task:        &Task{DetectStaleness: true},
+			lastSuccess: since.Add(time.Hour + 10*time.Second),
+			now:         since.Add(time.Hour + 30*time.Minute),
+			stale:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := NewJob("stale", tt.task, NewMemoryHistoryStore(), &logger.NopLogger{})
+			j.since = since
+			j.lastSuccess = tt.lastSuccess
+			reason := j.staleness(tt.now, schedule)
+			if got := reason != ""; got != tt.stale {
+				t.Errorf("unexpected staleness. got: %v (%s), want: %v", got, reason, tt.stale)
+			}
+		})
+	}
+}
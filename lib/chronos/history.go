@@ -0,0 +1,383 @@
+package chronos
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/xruins/chronos/lib/logger"
+)
+
+// HistoryDriver is the enum of the kinds of `HistoryStore`.
+type HistoryDriver string
+
+const (
+	// HistoryDriverUnknown is the default value of `HistoryDriver`.
+	// It is same to specify `file` driver.
+	HistoryDriverUnknown HistoryDriver = ""
+	// HistoryDriverFile is the driver to persist the history into files on the local disk.
+	HistoryDriverFile HistoryDriver = "file"
+	// HistoryDriverMemory is the driver to keep the history in memory.
+	// The history will be lost when Chronos worker stopped.
+	HistoryDriverMemory HistoryDriver = "memory"
+)
+
+const (
+	// DefaultHistoryLimit is the number of executions kept in the history when `Task.HistoryLimit` is not specified.
+	DefaultHistoryLimit = 100
+)
+
+// HistoryStore is the interface to persist executions and states of Jobs.
+type HistoryStore interface {
+	// Append appends an execution to the history of the task.
+	Append(name string, e *Execution) error
+	// List returns the executions of the task in chronological order.
+	// If `limit` is greater than 0, only the latest `limit` executions are returned.
+	List(name string, limit int) ([]*Execution, error)
+	// Prune deletes the executions of the task which exceed `limit` or started before `before`.
+	// `limit` of 0 or less and zero value of `before` mean no limitation.
+	// The latest execution is always kept to take over the count of successful executions.
+	Prune(name string, limit int, before time.Time) error
+	// LoadState returns the persisted state of the task.
+	// It returns the zero value of `TaskState` if the state has never been saved.
+	LoadState(name string) (*TaskState, error)
+	// SaveState persists the state of the task.
+	SaveState(name string, state *TaskState) error
+}
+
+// TaskState is the state of a task which survives restarts of Chronos worker.
+type TaskState struct {
+	// Paused is true if the task is paused.
+	Paused bool `json:"paused"`
+}
+
+// NewHistoryStore returns an instance of `HistoryStore` for the config.
+// It uses `FileHistoryStore` when conf is nil.
+// If `Dir` is not specified and the user cache directory is not available, it falls back to `MemoryHistoryStore`.
+func NewHistoryStore(conf *History, logger logger.Logger) (HistoryStore, error) {
+	if conf == nil {
+		conf = &History{}
+	}
+
+	switch conf.Driver {
+	case HistoryDriverMemory:
+		return NewMemoryHistoryStore(), nil
+	case HistoryDriverFile, HistoryDriverUnknown:
+		dir := conf.Dir
+		if dir == "" {
+			cacheDir, err := os.UserCacheDir()
+			if err != nil {
+				logger.Warnf("History is kept in memory since the directory for history cannot be determined (specify `history.dir` to persist it): %s", err)
+				return NewMemoryHistoryStore(), nil
+			}
+			dir = filepath.Join(cacheDir, "chronos", "history")
+		}
+		return NewFileHistoryStore(dir), nil
+	default:
+		return nil, fmt.Errorf("unknown history driver: %s", conf.Driver)
+	}
+}
+
+// pruneExecutions returns executions except for the ones to be pruned.
+// `executions` must be sorted in chronological order.
+func pruneExecutions(executions []*Execution, limit int, before time.Time) []*Execution {
+	if len(executions) == 0 {
+		return executions
+	}
+
+	start := 0
+	if limit > 0 && len(executions) > limit {
+		start = len(executions) - limit
+	}
+	if !before.IsZero() {
+		for start < len(executions) && executions[start].StartedAt.Before(before) {
+			start++
+		}
+	}
+	if start >= len(executions) {
+		start = len(executions) - 1
+	}
+	return executions[start:]
+}
+
+// MemoryHistoryStore is a `HistoryStore` which keeps the history in memory.
+type MemoryHistoryStore struct {
+	mu         sync.RWMutex
+	executions map[string][]*Execution
+	states     map[string]TaskState
+}
+
+// NewMemoryHistoryStore returns an instance of `MemoryHistoryStore`.
+func NewMemoryHistoryStore() *MemoryHistoryStore {
+	return &MemoryHistoryStore{
+		executions: make(map[string][]*Execution),
+		states:     make(map[string]TaskState),
+	}
+}
+
+// Append appends an execution to the history of the task.
+func (m *MemoryHistoryStore) Append(name string, e *Execution) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.executions[name] = append(m.executions[name], e)
+	return nil
+}
+
+// List returns the executions of the task in chronological order.
+func (m *MemoryHistoryStore) List(name string, limit int) ([]*Execution, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	executions := m.executions[name]
+	if limit > 0 && len(executions) > limit {
+		executions = executions[len(executions)-limit:]
+	}
+	ret := make([]*Execution, len(executions))
+	copy(ret, executions)
+	return ret, nil
+}
+
+// Prune deletes the executions of the task which exceed `limit` or started before `before`.
+func (m *MemoryHistoryStore) Prune(name string, limit int, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.executions[name] = pruneExecutions(m.executions[name], limit, before)
+	return nil
+}
+
+// LoadState returns the state of the task.
+func (m *MemoryHistoryStore) LoadState(name string) (*TaskState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	state := m.states[name]
+	return &state, nil
+}
+
+// SaveState stores the state of the task.
+func (m *MemoryHistoryStore) SaveState(name string, state *TaskState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[name] = *state
+	return nil
+}
+
+// FileHistoryStore is a `HistoryStore` which persists the history into files.
+// The history of each task is written into `<dir>/<task name>.jsonl` as JSON Lines,
+// and the state of each task is written into `<dir>/<task name>.state.json`.
+// The history file is read only once for each task and kept in memory afterwards,
+// so the files must not be modified by others while the store is used.
+type FileHistoryStore struct {
+	mu  sync.Mutex
+	dir string
+	// executions is the cache of the history files. The task which has not been read is absent.
+	executions map[string][]*Execution
+}
+
+// NewFileHistoryStore returns an instance of `FileHistoryStore`.
+// The directory is created on the first write.
+func NewFileHistoryStore(dir string) *FileHistoryStore {
+	return &FileHistoryStore{
+		dir:        dir,
+		executions: make(map[string][]*Execution),
+	}
+}
+
+func (f *FileHistoryStore) path(name string) string {
+	return filepath.Join(f.dir, url.PathEscape(name)+".jsonl")
+}
+
+func (f *FileHistoryStore) statePath(name string) string {
+	return filepath.Join(f.dir, url.PathEscape(name)+".state.json")
+}
+
+// load returns the executions of the task from the cache, reading the history file if it is not cached yet.
+func (f *FileHistoryStore) load(name string) ([]*Execution, error) {
+	if executions, ok := f.executions[name]; ok {
+		return executions, nil
+	}
+	executions, err := f.read(name)
+	if err != nil {
+		return nil, err
+	}
+	f.executions[name] = executions
+	return executions, nil
+}
+
+func (f *FileHistoryStore) read(name string) ([]*Execution, error) {
+	file, err := os.Open(f.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var executions []*Execution
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		e := &Execution{}
+		err := json.Unmarshal(scanner.Bytes(), e)
+		if err != nil {
+			return nil, fmt.Errorf("malformed history file: %w", err)
+		}
+		executions = append(executions, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	return executions, nil
+}
+
+// Append appends an execution to the history of the task.
+func (f *FileHistoryStore) Append(name string, e *Execution) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution: %w", err)
+	}
+
+	err = os.MkdirAll(f.dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	file, err := os.OpenFile(f.path(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	_, err = file.Write(append(b, '\n'))
+	if err != nil {
+		_ = file.Close()
+		// the file may be written partially
+		delete(f.executions, name)
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if executions, ok := f.executions[name]; ok {
+		f.executions[name] = append(executions, e)
+	}
+	return file.Close()
+}
+
+// List returns the executions of the task in chronological order.
+func (f *FileHistoryStore) List(name string, limit int) ([]*Execution, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	executions, err := f.load(name)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(executions) > limit {
+		executions = executions[len(executions)-limit:]
+	}
+	ret := make([]*Execution, len(executions))
+	copy(ret, executions)
+	return ret, nil
+}
+
+// Prune deletes the executions of the task which exceed `limit` or started before `before`.
+// The history file is replaced atomically.
+func (f *FileHistoryStore) Prune(name string, limit int, before time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	executions, err := f.load(name)
+	if err != nil {
+		return err
+	}
+	pruned := pruneExecutions(executions, limit, before)
+	if len(pruned) == len(executions) {
+		return nil
+	}
+
+	var b []byte
+	for _, e := range pruned {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal execution: %w", err)
+		}
+		b = append(append(b, line...), '\n')
+	}
+	err = f.replace(f.path(name), b)
+	if err != nil {
+		return err
+	}
+	f.executions[name] = pruned
+	return nil
+}
+
+// replace writes `b` into a temporary file and renames it to `path` to replace the file atomically.
+func (f *FileHistoryStore) replace(path string, b []byte) error {
+	err := os.MkdirAll(f.dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(f.dir, ".history-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	_, err = tmp.Write(b)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadState reads the state of the task from the file.
+func (f *FileHistoryStore) LoadState(name string) (*TaskState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state := &TaskState{}
+	b, err := os.ReadFile(f.statePath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	err = json.Unmarshal(b, state)
+	if err != nil {
+		return nil, fmt.Errorf("malformed state file: %w", err)
+	}
+	return state, nil
+}
+
+// SaveState writes the state of the task into the file.
+func (f *FileHistoryStore) SaveState(name string, state *TaskState) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return f.replace(f.statePath(name), b)
+}
@@ -0,0 +1,138 @@
+package chronos_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xruins/chronos/lib/chronos"
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestHistoryStore(t *testing.T) {
+	type pattern struct {
+		description string
+		store       chronos.HistoryStore
+	}
+
+	patterns := []*pattern{
+		{
+			description: "memory",
+			store:       chronos.NewMemoryHistoryStore(),
+		},
+		{
+			description: "file",
+			store:       chronos.NewFileHistoryStore(t.TempDir()),
+		},
+	}
+
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	executions := make([]*chronos.Execution, 5)
+	for i := range executions {
+		executions[i] = &chronos.Execution{
+			StartedAt:    base.Add(time.Duration(i) * time.Hour),
+			FinishedAt:   base.Add(time.Duration(i)*time.Hour + time.Minute),
+			Duration:     time.Minute,
+			Status:       chronos.ExecutionStatusSucceeded,
+			SuccessCount: i + 1,
+		}
+	}
+
+	for _, p := range patterns {
+		t.Run(p.description, func(t *testing.T) {
+			for _, e := range executions {
+				err := p.store.Append("hello", e)
+				if err != nil {
+					t.Fatalf("failed to append execution: %s", err)
+				}
+			}
+
+			got, err := p.store.List("hello", 2)
+			if err != nil {
+				t.Fatalf("failed to list executions: %s", err)
+			}
+			if diff := cmp.Diff(executions[3:], got); diff != "" {
+				t.Errorf("unexpected executions with limit. diff: %s", diff)
+			}
+
+			got, err = p.store.List("unknown", 0)
+			if err != nil {
+				t.Fatalf("failed to list executions: %s", err)
+			}
+			if len(got) != 0 {
+				t.Errorf("unexpected executions for unknown task. got: %v", got)
+			}
+
+			err = p.store.Prune("hello", 4, base.Add(2*time.Hour))
+			if err != nil {
+				t.Fatalf("failed to prune executions: %s", err)
+			}
+			got, err = p.store.List("hello", 0)
+			if err != nil {
+				t.Fatalf("failed to list executions: %s", err)
+			}
+			if diff := cmp.Diff(executions[2:], got); diff != "" {
+				t.Errorf("unexpected executions after pruning. diff: %s", diff)
+			}
+
+			err = p.store.Prune("hello", 0, base.Add(24*time.Hour))
+			if err != nil {
+				t.Fatalf("failed to prune executions: %s", err)
+			}
+			got, err = p.store.List("hello", 0)
+			if err != nil {
+				t.Fatalf("failed to list executions: %s", err)
+			}
+			if diff := cmp.Diff(executions[4:], got); diff != "" {
+				t.Errorf("the latest execution must be kept. diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFileHistoryStorePersistence(t *testing.T) {
+	dir := t.TempDir()
+	store := chronos.NewFileHistoryStore(dir)
+
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var executions []*chronos.Execution
+	for i := 0; i < 3; i++ {
+		e := &chronos.Execution{StartedAt: base.Add(time.Duration(i) * time.Hour), Status: chronos.ExecutionStatusSucceeded}
+		executions = append(executions, e)
+		if err := store.Append("hello", e); err != nil {
+			t.Fatalf("failed to append execution: %s", err)
+		}
+		// the history is cached after the first read
+		if _, err := store.List("hello", 0); err != nil {
+			t.Fatalf("failed to list executions: %s", err)
+		}
+	}
+	if err := store.Prune("hello", 2, time.Time{}); err != nil {
+		t.Fatalf("failed to prune executions: %s", err)
+	}
+
+	got, err := chronos.NewFileHistoryStore(dir).List("hello", 0)
+	if err != nil {
+		t.Fatalf("failed to list executions: %s", err)
+	}
+	if diff := cmp.Diff(executions[1:], got); diff != "" {
+		t.Errorf("unexpected executions in the file. diff: %s", diff)
+	}
+}
+
+func TestNewHistoryStoreWithoutCacheDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the user cache directory is determined by the environment variables only on Linux")
+	}
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "")
+
+	store, err := chronos.NewHistoryStore(nil, &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create history store: %s", err)
+	}
+	if _, ok := store.(*chronos.MemoryHistoryStore); !ok {
+		t.Errorf("the store must fall back to memory. got: %T", store)
+	}
+}
@@ -3,8 +3,8 @@ package chronos
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"math"
 	"os"
 	"os/exec"
 	"strings"
@@ -27,20 +27,63 @@ const (
 	StateUnhealthy
 )
 
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateHealthy:
+		return "healthy"
+	case StateUnhealthy:
+		return "unhealthy"
+	default:
+		return "unknown"
+	}
+}
+
 // Job represents a unit to execute a task periodically.
 // It runs command and have the information of the command to execute and past execution.
 type Job struct {
-	name       string
-	mu         sync.RWMutex
-	task       *Task
-	retryCount int
-	State      State
-	execution  []*Execution
-	logger     logger.Logger
+	name        string
+	mu          sync.RWMutex
+	task        *Task
+	retryCount  int
+	State       State
+	health      health
+	paused      bool
+	since       time.Time
+	runs        *jobRuns
+	lastSuccess time.Time
+	history     HistoryStore
+	metrics     *metrics
+	notifier    *notifier
+	logger      logger.Logger
+	// satisfied are the names of the tasks in `DependsOn` which have succeeded since the last execution triggered by them.
+	satisfied map[string]bool
+	// onFinish is called when a run of the Job finished, to trigger the downstream tasks.
+	onFinish func(j *Job, succeeded bool)
+	// excluded returns the description of the exclusion which `now` falls in. See `Exclusion`.
+	excluded func(j *Job, now time.Time) (string, error)
 }
 
-func (j *Job) generateTemplateFuncMap(env map[string]string) map[string]interface{} {
+// jobRun represents an in-flight run of a Job.
+type jobRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// jobRuns is the set of in-flight runs of a Job.
+// It is shared with the Job which replaces the Job on reload, to apply `ConcurrencyPolicy` to the previous runs.
+type jobRuns struct {
+	mu   sync.Mutex
+	runs map[*jobRun]struct{}
+}
+
+// generateTemplateFuncMap returns the functions available in templates.
+// `attempt` is the number of the attempt in a series of retry, which starts with 0.
+func (j *Job) generateTemplateFuncMap(env map[string]string, attempt int) map[string]interface{} {
 	return map[string]interface{}{
+		"attempt": func() int {
+			return attempt + 1
+		},
 		"env": func(key string) string {
 			value, ok := env[key]
 			if ok {
@@ -54,14 +97,15 @@ func (j *Job) generateTemplateFuncMap(env map[string]string) map[string]interfac
 		"time": func(t string) string {
 			return time.Now().Format(t)
 		},
-		"count": func() int {
-			var count int
-			for _, e := range j.execution {
-				if e.succeeded {
-					count++
-				}
+		"count": func() (int, error) {
+			last, err := j.lastExecution()
+			if err != nil {
+				return 0, err
 			}
-			return count + 1
+			if last == nil {
+				return 1, nil
+			}
+			return last.SuccessCount + 1, nil
 		},
 	}
 }
@@ -88,16 +132,76 @@ func (j *Job) generateEnvVariables(propagate bool) map[string]string {
 }
 
 // NewJob returns an instance of `Job`.
-func NewJob(name string, task *Task, logger logger.Logger) *Job {
+// The executions of the Job are recorded into `history`.
+func NewJob(name string, task *Task, history HistoryStore, logger logger.Logger) *Job {
 	return &Job{
-		name:   name,
-		task:   task,
-		mu:     sync.RWMutex{},
-		State:  StateHealthy,
-		logger: logger,
+		name:    name,
+		task:    task,
+		mu:      sync.RWMutex{},
+		State:   StateHealthy,
+		since:   time.Now(),
+		runs:    &jobRuns{runs: make(map[*jobRun]struct{})},
+		history: history,
+		logger:  logger,
 	}
 }
 
+// lastExecution returns the latest execution in the history.
+// It returns nil when the Job has never been executed.
+func (j *Job) lastExecution() (*Execution, error) {
+	executions, err := j.history.List(j.name, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the history of Task `%s`: %w", j.name, err)
+	}
+	if len(executions) == 0 {
+		return nil, nil
+	}
+	return executions[0], nil
+}
+
+// record appends the execution to the history and prunes the history according to the retention of the task.
+func (j *Job) record(e *Execution) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	last, err := j.lastExecution()
+	if err != nil {
+		j.logger.Warnf("Task `%s` failed to get the last execution: %s", j.name, err)
+	}
+	if last != nil {
+		e.SuccessCount = last.SuccessCount
+	}
+	if e.Status == ExecutionStatusSucceeded {
+		e.SuccessCount++
+		j.lastSuccess = e.FinishedAt
+	}
+
+	err = j.history.Append(j.name, e)
+	if err != nil {
+		j.logger.Warnf("Task `%s` failed to record the execution: %s", j.name, err)
+		return
+	}
+
+	limit := j.task.HistoryLimit
+	if limit == 0 {
+		limit = DefaultHistoryLimit
+	}
+	var before time.Time
+	if j.task.HistoryMaxAge > 0 {
+		before = time.Now().Add(-time.Duration(j.task.HistoryMaxAge) * time.Second)
+	}
+	err = j.history.Prune(j.name, limit, before)
+	if err != nil {
+		j.logger.Warnf("Task `%s` failed to prune the history: %s", j.name, err)
+	}
+}
+
+// History returns the latest `limit` executions of the Job in chronological order.
+// If `limit` is 0 or less, it returns all executions in the history.
+func (j *Job) History(limit int) ([]*Execution, error) {
+	return j.history.List(j.name, limit)
+}
+
 // IsHealthy returns `true` for healthy Job.
 // Otherwise, it returns `false`.
 func (j *Job) IsHealthy() bool {
@@ -106,45 +210,239 @@ func (j *Job) IsHealthy() bool {
 	return j.State == StateHealthy
 }
 
+// HealthReason returns the reason why the Job is unhealthy.
+// It returns empty string for healthy Job.
+func (j *Job) HealthReason() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.State != StateUnhealthy {
+		return ""
+	}
+	return j.health.reason
+}
+
+// observe updates the state of the Job with the result of a run, and returns the states before and after that.
+func (j *Job) observe(failed bool, e *Execution) (State, State) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	prev := j.State
+	j.State = j.health.observe(j.task, prev, failed, e)
+	return prev, j.State
+}
+
+// Name returns the name of the task executed by the Job.
+func (j *Job) Name() string {
+	return j.name
+}
+
+// IsPaused returns `true` if the Job is paused.
+func (j *Job) IsPaused() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.paused
+}
+
+// Pause stops the scheduled executions of the Job until `Resume` is called.
+// The paused state is persisted into the history store to survive restarts.
+func (j *Job) Pause() error {
+	return j.setPaused(true)
+}
+
+// Resume restarts the scheduled executions of the Job paused by `Pause`.
+func (j *Job) Resume() error {
+	return j.setPaused(false)
+}
+
+func (j *Job) setPaused(paused bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := j.history.SaveState(j.name, &TaskState{Paused: paused})
+	if err != nil {
+		return fmt.Errorf("failed to save the state of Task `%s`: %w", j.name, err)
+	}
+	j.paused = paused
+	return nil
+}
+
+// loadState restores the state of the Job from the history store.
+func (j *Job) loadState() error {
+	state, err := j.history.LoadState(j.name)
+	if err != nil {
+		return fmt.Errorf("failed to load the state of Task `%s`: %w", j.name, err)
+	}
+	executions, err := j.history.List(j.name, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get the history of Task `%s`: %w", j.name, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.paused = state.Paused
+	for i := len(executions) - 1; i >= 0; i-- {
+		if executions[i].Status == ExecutionStatusSucceeded {
+			j.lastSuccess = executions[i].FinishedAt
+			break
+		}
+	}
+	return nil
+}
+
+// LastSuccess returns the time when the last successful execution finished.
+// It returns zero value if the Job has never succeeded.
+func (j *Job) LastSuccess() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.lastSuccess
+}
+
+// state returns the current state of the Job.
+func (j *Job) state() State {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.State
+}
+
 // Execute executes the command defined in `task`.
-func (j *Job) Execute(ctx context.Context) error {
+// It returns the information of the execution even if it failed.
+func (j *Job) Execute(ctx context.Context) (*Execution, error) {
+	return j.executeAttempt(ctx, 0)
+}
+
+// executeAttempt executes the command as the `attempt`-th attempt in a series of retry.
+func (j *Job) executeAttempt(ctx context.Context, attempt int) (*Execution, error) {
+	execution := &Execution{
+		Attempt:   attempt,
+		StartedAt: time.Now(),
+		ExitCode:  -1,
+	}
+	err := j.execute(ctx, execution)
+	execution.FinishedAt = time.Now()
+	execution.Duration = execution.FinishedAt.Sub(execution.StartedAt)
+	if errors.Is(err, errTimedOut) {
+		execution.Status = ExecutionStatusTimedOut
+		execution.Error = err.Error()
+		return execution, err
+	}
+	if err != nil {
+		execution.Status = ExecutionStatusFailed
+		execution.Error = err.Error()
+		return execution, err
+	}
+	execution.Status = ExecutionStatusSucceeded
+	return execution, nil
+}
+
+// renderTemplate returns the text to which the templates are applied.
+func renderTemplate(text string, tf template.FuncMap) (string, error) {
+	tmpl, err := template.New("template").Funcs(tf).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to create template. templateText: %s, err: %w", text, err)
+	}
+
+	w := new(bytes.Buffer)
+	err = tmpl.Execute(w, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to apply template. templateText: %s, err: %w", text, err)
+	}
+	return w.String(), nil
+}
+
+func (j *Job) execute(ctx context.Context, execution *Execution) error {
 	var cancel func()
 	if j.task.Timeout != 0 {
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(j.task.Timeout)*time.Second)
+		ctx, cancel = context.WithTimeoutCause(ctx, time.Duration(j.task.Timeout)*time.Second, errTimedOut)
 		defer cancel()
 	}
 
 	env := j.generateEnvVariables(j.task.PropagateEnv)
 	args := make([]string, len(j.task.Args))
 	copy(args, j.task.Args)
+	script := j.task.Script
 	if j.task.UseTemplate {
-		tf := j.generateTemplateFuncMap(env)
+		tf := j.generateTemplateFuncMap(env, execution.Attempt)
 
 		for i, arg := range args {
-			var err error
-			tmpl := template.Must(template.New("template").Funcs(tf).Parse(arg))
+			rendered, err := renderTemplate(arg, tf)
 			if err != nil {
-				return fmt.Errorf("failed to create template. templateText: %s, err: %w", arg, err)
+				return err
 			}
-
-			w := new(bytes.Buffer)
-			err = tmpl.Execute(w, nil)
+			j.logger.Debugf("transform args. before: %s, after:%s", args[i], rendered)
+			args[i] = rendered
+		}
+		if script != "" {
+			rendered, err := renderTemplate(script, tf)
 			if err != nil {
-				return fmt.Errorf("failed to apply template. templateText: %s, err: %w", arg, err)
+				return err
 			}
+			script = rendered
+		}
+	}
 
-			j.logger.Debugf("transform args. before: %s, after:%s", args[i], w.String())
-			args[i] = w.String()
+	var scriptPath string
+	if script != "" {
+		var err error
+		scriptPath, err = writeScript(script)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = os.Remove(scriptPath)
+		}()
+		err = j.task.chownScript(scriptPath)
+		if err != nil {
+			return err
 		}
 	}
 
-	j.logger.Infof("Task `%s` started to execute command. command: %s %s", j.name, j.task.Command, strings.Join(j.task.Args, " "))
-	cmd := exec.CommandContext(ctx, j.task.Command, args...)
+	name, args := j.task.commandLine(j.name, args, scriptPath)
+	if j.task.Script != "" {
+		j.logger.Infof("Task `%s` started to execute script. command: %s %s", j.name, name, strings.Join(args, " "))
+	} else {
+		j.logger.Infof("Task `%s` started to execute command. command: %s %s", j.name, j.task.Command, strings.Join(j.task.Args, " "))
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	err := j.task.prepareCommand(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to prepare command: %w", err)
+	}
+
+	// the grace period to kill the command is measured from the first signal
+	var signaledAt time.Time
+	signal := cmd.Cancel
+	cmd.Cancel = func() error {
+		signaledAt = time.Now()
+		return signal()
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
-	err := cmd.Run()
+	err = cmd.Start()
+	if err == nil {
+		err = cmd.Wait()
+		if !signaledAt.IsZero() {
+			// clean up the descendants of the command left in the process group
+			j.task.reap(cmd, signaledAt.Add(j.task.killGracePeriod()))
+		}
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(context.Cause(ctx), errTimedOut):
+		// the command is regarded as timed out even if it handled the signal and exited successfully
+		if err == nil {
+			err = errors.New("the command was terminated")
+		}
+		err = fmt.Errorf("%w after %d seconds: %s", errTimedOut, j.task.Timeout, err)
+	case errors.As(err, &exitErr) && j.task.isSuccessExitCode(exitErr.ExitCode()):
+		j.logger.Infof("Task `%s` exited with code %d, which is regarded as success.", j.name, exitErr.ExitCode())
+		err = nil
+	}
+	if cmd.ProcessState != nil {
+		execution.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	execution.Stdout = truncateOutput(stdout.String())
+	execution.Stderr = truncateOutput(stderr.String())
 	if out := stdout.String(); len(out) != 0 {
 		trimmed := strings.TrimSuffix(out, "\n")
 		j.logger.Infof("Task `%s` outputted to STDOUT: %s", j.name, trimmed)
@@ -161,63 +459,257 @@ func (j *Job) Execute(ctx context.Context) error {
 }
 
 // Run invokes `Execute` with retry process.
+// It does nothing while the Job is paused.
 // `Run` is named to satisfy cron.Job interface.
 func (j *Job) Run() {
-	ctx := context.Background()
-	retryLimit := j.task.RetryLimit
+	j.RunContext(context.Background())
+}
+
+// RunContext is same to `Run` except that the execution is cancelled when `ctx` is done.
+func (j *Job) RunContext(ctx context.Context) {
+	j.runUnlessPaused(ctx, runCause{trigger: TriggerSchedule, scheduledAt: time.Now().Truncate(time.Second)})
+}
+
+// runUnlessPaused invokes `Execute` with retry process for the execution caused by `cause`.
+// It does nothing while the Job is paused, and skips the execution during the exclusions.
+func (j *Job) runUnlessPaused(ctx context.Context, cause runCause) {
+	if j.IsPaused() {
+		j.logger.Infof("Task `%s` is paused. skipped the execution.", j.name)
+		j.metrics.skip(j.name, SkipReasonPaused)
+		return
+	}
+	if reason := j.exclusion(); reason != "" {
+		j.skip(cause, SkipReasonExcluded, fmt.Sprintf("excluded by %s", reason))
+		return
+	}
+	j.run(ctx, cause)
+}
+
+// Trigger invokes `Execute` with retry process regardless of the schedule.
+// It executes the command even if the Job is paused.
+// It returns the last execution in the series of retry.
+func (j *Job) Trigger(ctx context.Context) *Execution {
+	return j.run(ctx, runCause{trigger: TriggerManual})
+}
 
-	isRetryable := j.task.RetryLimit != RetryLimitNever
-	isInfiniteRetry := j.task.RetryLimit == RetryLimitInfinite
+// Running returns the number of in-flight runs of the Job.
+func (j *Job) Running() int {
+	j.runs.mu.Lock()
+	defer j.runs.mu.Unlock()
+	return len(j.runs.runs)
+}
+
+// acquire registers a new run of the Job according to `ConcurrencyPolicy` of the task.
+// It returns false if the run must be skipped.
+// Otherwise, it returns the context for the run and the function to be called when the run finished.
+func (j *Job) acquire(ctx context.Context) (context.Context, func(), bool) {
+	j.runs.mu.Lock()
+	if len(j.runs.runs) > 0 {
+		switch j.task.ConcurrencyPolicy {
+		case ConcurrencyPolicyForbid:
+			j.runs.mu.Unlock()
+			return nil, nil, false
+		case ConcurrencyPolicyReplace:
+			var running []*jobRun
+			for r := range j.runs.runs {
+				running = append(running, r)
+			}
+			j.runs.mu.Unlock()
 
-	for i := 0; ; i++ {
-		execution := &Execution{
-			count:        i,
-			executedTime: time.Now(),
+			j.logger.Warnf("Task `%s` cancels %d running execution(s) to replace with the new one.", j.name, len(running))
+			for _, r := range running {
+				r.cancel()
+				<-r.done
+			}
+			j.runs.mu.Lock()
 		}
-		err := j.Execute(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r := &jobRun{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	runs := j.runs
+	runs.runs[r] = struct{}{}
+	runs.mu.Unlock()
+
+	return ctx, func() {
+		runs.mu.Lock()
+		delete(runs.runs, r)
+		runs.mu.Unlock()
+		cancel()
+		close(r.done)
+	}, true
+}
+
+// skip records the execution skipped because of `reason`.
+func (j *Job) skip(cause runCause, reason SkipReason, message string) *Execution {
+	j.logger.Warnf("Task `%s` skipped the execution: %s", j.name, message)
+	j.metrics.skip(j.name, reason)
+	now := time.Now()
+	execution := &Execution{
+		StartedAt:  now,
+		FinishedAt: now,
+		Status:     ExecutionStatusSkipped,
+		ExitCode:   -1,
+		Error:      message,
+	}
+	cause.apply(execution)
+	j.record(execution)
+	return execution
+}
+
+// runCause is the cause of a run of the Job.
+type runCause struct {
+	trigger Trigger
+	// scheduledAt is the time when the execution was scheduled. It is zero value for the manual executions.
+	scheduledAt time.Time
+	// triggeredBy is the name of the upstream task for the executions triggered by dependencies.
+	triggeredBy string
+}
+
+// apply records the cause into the execution.
+func (c runCause) apply(e *Execution) {
+	e.Trigger = c.trigger
+	e.ScheduledAt = c.scheduledAt
+	e.TriggeredBy = c.triggeredBy
+}
+
+// run invokes `Execute` with retry process and returns the last execution.
+func (j *Job) run(ctx context.Context, cause runCause) *Execution {
+	ctx, done, ok := j.acquire(ctx)
+	if !ok {
+		return j.skip(cause, SkipReasonConcurrency, "the previous execution is still running")
+	}
+	defer done()
+	j.metrics.runStarted(j.name, cause.trigger)
+
+	backoff := newBackoff(j.task)
+	startedAt := time.Now()
+	var execution *Execution
+	for attempt := 0; ; attempt++ {
+		var err error
+		execution, err = j.executeAttempt(ctx, attempt)
+		cause.apply(execution)
+		j.record(execution)
+		j.metrics.executed(j.name, execution)
 		if err == nil {
-			execution.succeeded = true
 			j.logger.Infof("Task `%s` finished to execute command successfully.", j.name)
-			j.execution = append(j.execution, execution)
+			j.metrics.succeeded(j.name)
 
-			// set healthy state when succeeded to execute the task
-			j.mu.Lock()
-			j.State = StateHealthy
-			j.mu.Unlock()
-			return
+			prev, state := j.observe(false, execution)
+			if prev == StateUnhealthy && state == StateHealthy {
+				j.logger.Infof("Task `%s` recovered.", j.name)
+				j.notifier.notify(j.name, j.task, NotificationEventRecovery, execution)
+			}
+			j.notifier.notify(j.name, j.task, NotificationEventSuccess, execution)
+			j.finish(true)
+			return execution
 		}
-
-		j.logger.Warnf("Task `%s` failed to execute command (failed %d of %d, will retry). err: %s", j.name, i, int(retryLimit), err)
-		execution.err = err
-		j.execution = append(j.execution, execution)
-
-		retryWait := time.Duration(j.task.RetryWait) * time.Second
-		if j.task.RetryType == RetryTypeExponential {
-			retryWait = time.Duration(int(math.Pow(2, float64(i)))*j.task.RetryWait) * time.Second
+		if ctx.Err() != nil {
+			j.logger.Warnf("Task `%s` was cancelled. err: %s", j.name, err)
+			return execution
 		}
-		if !isInfiniteRetry && !isRetryable || i >= int(retryLimit) {
+
+		wait, reason, ok := backoff.retry(attempt, execution, time.Since(startedAt))
+		if !ok {
+			j.logger.Warnf("Task `%s` failed to execute command (attempt %d, will not retry: %s). err: %s", j.name, attempt+1, reason, err)
 			break
 		}
+		j.logger.Warnf("Task `%s` failed to execute command (attempt %d, will retry in %s). err: %s", j.name, attempt+1, wait, err)
 
-		time.Sleep(retryWait)
-		i++
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			j.logger.Warnf("Task `%s` was cancelled while waiting for retry.", j.name)
+			return execution
+		}
+		j.metrics.retried(j.name)
 	}
 
+	j.metrics.failed(j.name)
+	defer j.finish(false)
 	if j.task.Fallthrough {
-		return
+		return execution
 	}
-	j.logger.Errorf("Task `%s` exceeded to retry limit.", j.name)
-	// set unhealthy state when failed to execute task
-	j.mu.Lock()
-	j.State = StateUnhealthy
-	j.mu.Unlock()
-	return
+	j.logger.Errorf("Task `%s` failed after %d attempt(s).", j.name, execution.Attempt+1)
+	prev, state := j.observe(true, execution)
+	if prev != StateUnhealthy && state == StateUnhealthy {
+		j.logger.Errorf("Task `%s` became unhealthy: %s", j.name, j.HealthReason())
+	}
+	j.notifier.notify(j.name, j.task, NotificationEventFailure, execution)
+	return execution
+}
+
+// Trigger is the enum of the causes of executions.
+type Trigger string
+
+const (
+	// TriggerSchedule is the trigger of executions invoked by the scheduler.
+	TriggerSchedule Trigger = "schedule"
+	// TriggerManual is the trigger of executions invoked by the API.
+	TriggerManual Trigger = "manual"
+	// TriggerCatchup is the trigger of executions missed while the worker was down. See `Task.Catchup`.
+	TriggerCatchup Trigger = "catchup"
+	// TriggerDependency is the trigger of executions invoked by the completion of the upstream tasks.
+	TriggerDependency Trigger = "dependency"
+)
+
+// ExecutionStatus is the enum of the results of executions.
+type ExecutionStatus string
+
+const (
+	// ExecutionStatusSucceeded is the status of the execution which finished successfully.
+	ExecutionStatusSucceeded ExecutionStatus = "succeeded"
+	// ExecutionStatusFailed is the status of the execution which failed.
+	ExecutionStatusFailed ExecutionStatus = "failed"
+	// ExecutionStatusTimedOut is the status of the execution which was terminated because of `Task.Timeout`.
+	ExecutionStatusTimedOut ExecutionStatus = "timed_out"
+	// ExecutionStatusSkipped is the status of the execution which was skipped without executing the command.
+	ExecutionStatusSkipped ExecutionStatus = "skipped"
+)
+
+// errTimedOut is the error of the execution terminated because of `Task.Timeout`.
+var errTimedOut = errors.New("timed out")
+
+// maxOutputSize is the maximum bytes of STDOUT and STDERR to be recorded for each execution.
+const maxOutputSize = 64 * 1024
+
+// truncateOutput returns the tail of the output which fits within `maxOutputSize`.
+func truncateOutput(out string) string {
+	if len(out) <= maxOutputSize {
+		return out
+	}
+	return out[len(out)-maxOutputSize:]
 }
 
 // Execution represents an information of past command executions of `Job`.
 type Execution struct {
-	count        int
-	executedTime time.Time
-	err          error
-	succeeded    bool
+	// Attempt is the number of attempts in a series of retry. It starts with 0.
+	Attempt int `json:"attempt"`
+	// StartedAt is the time when the execution started.
+	StartedAt time.Time `json:"started_at"`
+	// FinishedAt is the time when the execution finished.
+	FinishedAt time.Time `json:"finished_at"`
+	// Duration is the time taken for the execution.
+	Duration time.Duration `json:"duration"`
+	// Trigger is the cause of the execution.
+	Trigger Trigger `json:"trigger"`
+	// ScheduledAt is the time when the execution was scheduled. It is zero value for the manual executions.
+	ScheduledAt time.Time `json:"scheduled_at"`
+	// TriggeredBy is the name of the upstream task for the executions triggered by dependencies.
+	TriggeredBy string `json:"triggered_by,omitempty"`
+	// Status is the result of the execution.
+	Status ExecutionStatus `json:"status"`
+	// ExitCode is the exit code of the command. It is -1 if the command did not exit normally.
+	ExitCode int `json:"exit_code"`
+	// Error is the message of the error occurred on the execution.
+	Error string `json:"error,omitempty"`
+	// Stdout is the tail of the output to STDOUT.
+	Stdout string `json:"stdout,omitempty"`
+	// Stderr is the tail of the output to STDERR.
+	Stderr string `json:"stderr,omitempty"`
+	// SuccessCount is the number of successful executions of the task until this execution.
+	SuccessCount int `json:"success_count"`
 }
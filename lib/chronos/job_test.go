@@ -8,44 +8,56 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/xruins/chronos/lib/logger"
 )
 
 func TestGenerateTemplateFuncMap(t *testing.T) {
-	j := &Job{
-		execution: []*Execution{
-			{
-				count:        1,
-				executedTime: time.Now().Add(-3 * time.Hour),
-				succeeded:    true,
-			},
-			{
-				count:        2,
-				executedTime: time.Now().Add(-2 * time.Hour),
-				succeeded:    false,
-			},
-			{
-				count:        3,
-				executedTime: time.Now().Add(-1 * time.Hour),
-				succeeded:    true,
-			},
+	history := NewMemoryHistoryStore()
+	executions := []*Execution{
+		{
+			Attempt:      0,
+			StartedAt:    time.Now().Add(-3 * time.Hour),
+			Status:       ExecutionStatusSucceeded,
+			SuccessCount: 1,
+		},
+		{
+			Attempt:      0,
+			StartedAt:    time.Now().Add(-2 * time.Hour),
+			Status:       ExecutionStatusFailed,
+			SuccessCount: 1,
 		},
+		{
+			Attempt:      1,
+			StartedAt:    time.Now().Add(-1 * time.Hour),
+			Status:       ExecutionStatusSucceeded,
+			SuccessCount: 2,
+		},
+	}
+	for _, e := range executions {
+		_ = history.Append("test", e)
+	}
+	j := &Job{
+		name:    "test",
+		history: history,
 	}
 
 	tf := j.generateTemplateFuncMap(map[string]string{
 		"foo":  "bar",
 		"hoge": "fuga",
-	})
+	}, 1)
 
 	args1 := `
 {{env "foo"}}
 {{env "hoge"}}
-{{count}}`
+{{count}}
+{{attempt}}`
 
 	got := applyTemplate(t, tf, args1)
 	want := `
 bar
 fuga
-3`
+3
+2`
 	if diff := cmp.Diff(got, want); diff != "" {
 		t.Errorf("unexpected applied value. diff: %s", diff)
 	}
@@ -72,3 +84,97 @@ func applyTemplate(t *testing.T, tf map[string]interface{}, example string) stri
 	}
 	return w.String()
 }
+
+func TestJobRunRecordsHistory(t *testing.T) {
+	history := NewMemoryHistoryStore()
+	j := NewJob("test", &Task{
+		Command:      "sh",
+		Args:         []string{"-c", "echo hello; echo world >&2"},
+		HistoryLimit: 2,
+	}, history, &logger.NopLogger{})
+
+	for i := 0; i < 3; i++ {
+		j.Run()
+	}
+
+	got, err := j.History(0)
+	if err != nil {
+		t.Fatalf("failed to get history: %s", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("unexpected length of history. got: %d, want: %d", len(got), 2)
+	}
+	last := got[len(got)-1]
+	want := &Execution{
+		Trigger:      TriggerSchedule,
+		Status:       ExecutionStatusSucceeded,
+		ExitCode:     0,
+		Stdout:       "hello\n",
+		Stderr:       "world\n",
+		SuccessCount: 3,
+	}
+	opt := cmpopts.IgnoreFields(Execution{}, "StartedAt", "FinishedAt", "Duration", "ScheduledAt")
+	if diff := cmp.Diff(want, last, opt); diff != "" {
+		t.Errorf("unexpected execution. diff: %s", diff)
+	}
+}
+
+func TestJobConcurrencyPolicy(t *testing.T) {
+	type pattern struct {
+		description string
+		policy      ConcurrencyPolicy
+		want        []ExecutionStatus
+	}
+
+	patterns := []*pattern{
+		{
+			description: "allow runs concurrently",
+			policy:      ConcurrencyPolicyAllow,
+			want:        []ExecutionStatus{ExecutionStatusSucceeded, ExecutionStatusSucceeded},
+		},
+		{
+			description: "forbid skips the new execution",
+			policy:      ConcurrencyPolicyForbid,
+			want:        []ExecutionStatus{ExecutionStatusSkipped, ExecutionStatusSucceeded},
+		},
+		{
+			description: "replace cancels the running execution",
+			policy:      ConcurrencyPolicyReplace,
+			want:        []ExecutionStatus{ExecutionStatusFailed, ExecutionStatusSucceeded},
+		},
+	}
+
+	for _, p := range patterns {
+		t.Run(p.description, func(t *testing.T) {
+			history := NewMemoryHistoryStore()
+			j := NewJob("test", &Task{
+				Command:           "sleep",
+				Args:              []string{"1"},
+				ConcurrencyPolicy: p.policy,
+			}, history, &logger.NopLogger{})
+
+			done := make(chan struct{})
+			go func() {
+				j.Run()
+				close(done)
+			}()
+			for j.Running() == 0 {
+				time.Sleep(10 * time.Millisecond)
+			}
+			j.Run()
+			<-done
+
+			executions, err := j.History(0)
+			if err != nil {
+				t.Fatalf("failed to get history: %s", err)
+			}
+			got := make([]ExecutionStatus, 0, len(executions))
+			for _, e := range executions {
+				got = append(got, e.Status)
+			}
+			if diff := cmp.Diff(p.want, got); diff != "" {
+				t.Errorf("unexpected statuses of executions. diff: %s", diff)
+			}
+		})
+	}
+}
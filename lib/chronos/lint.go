@@ -0,0 +1,272 @@
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Severity is the enum of the severities of issues found by `LintConfig`.
+type Severity string
+
+const (
+	// SeverityError is the severity of the issue which prevents the worker from running tasks properly.
+	SeverityError Severity = "error"
+	// SeverityWarning is the severity of the issue which may be a mistake.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem of the config found by `LintConfig`.
+type Issue struct {
+	// Severity is the severity of the issue.
+	Severity Severity `json:"severity"`
+	// File is the name of the config file.
+	File string `json:"file"`
+	// Field is the path to the field which has the issue. (e.g. `tasks.hello.schedule`)
+	// It is empty for the issue on the whole file.
+	Field string `json:"field,omitempty"`
+	// Message is the description of the issue.
+	Message string `json:"message"`
+}
+
+// String returns the human-readable representation of the issue.
+func (i *Issue) String() string {
+	location := i.File
+	if i.Field != "" {
+		location += ": " + i.Field
+	}
+	return fmt.Sprintf("%s: %s: %s", location, i.Severity, i.Message)
+}
+
+// HasError returns true if `issues` contain any issue of `SeverityError`.
+func HasError(issues []*Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// linter collects issues of a config.
+type linter struct {
+	filename string
+	issues   []*Issue
+}
+
+func (l *linter) errorf(field string, format string, v ...interface{}) {
+	l.issues = append(l.issues, &Issue{Severity: SeverityError, File: l.filename, Field: field, Message: fmt.Sprintf(format, v...)})
+}
+
+func (l *linter) warnf(field string, format string, v ...interface{}) {
+	l.issues = append(l.issues, &Issue{Severity: SeverityWarning, File: l.filename, Field: field, Message: fmt.Sprintf(format, v...)})
+}
+
+// namespacePattern matches the keys of maps in the namespaces of validator. (e.g. `[hello]` of `Config.tasks[hello]`)
+var namespacePattern = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// fieldPath converts the namespace of validator into the path of the field in config files.
+func fieldPath(namespace string) string {
+	_, path, _ := strings.Cut(namespace, ".")
+	return namespacePattern.ReplaceAllString(path, ".$1")
+}
+
+// LintConfig checks the config file deeply, including the problems which the worker only discovers at runtime.
+// It returns the issues sorted by the field.
+func LintConfig(filename string) []*Issue {
+	l := &linter{filename: filename}
+
+	conf, err := LoadConfig(filename)
+	var verrs validator.ValidationErrors
+	var derr *DependencyError
+	switch {
+	case errors.As(err, &verrs):
+		for _, e := range verrs {
+			switch e.Tag() {
+			case "schedule":
+				l.errorf(fieldPath(e.Namespace()), "malformed schedule: %s", validateSchedule(e.Value().(string)))
+				continue
+			case "exclusion":
+				_, err := parseExclusionSpec(e.Value().(string))
+				l.errorf(fieldPath(e.Namespace()), "malformed exclusion: %s", err)
+				continue
+			case "signal":
+				_, err := (&Task{KillSignal: e.Value().(string)}).killSignal()
+				l.errorf(fieldPath(e.Namespace()), "%s", err)
+				continue
+			case "regexp":
+				_, err := regexp.Compile(e.Value().(string))
+				l.errorf(fieldPath(e.Namespace()), "malformed regular expression: %s", err)
+				continue
+			case "umask":
+				_, err := parseUmask(e.Value().(string))
+				l.errorf(fieldPath(e.Namespace()), "%s", err)
+				continue
+			}
+			tag := e.Tag()
+			if e.Param() != "" && !strings.Contains(tag, "=") {
+				tag += "=" + e.Param()
+			}
+			l.errorf(fieldPath(e.Namespace()), "failed on `%s` validation. value: %v", tag, e.Value())
+		}
+		return l.issues
+	case errors.As(err, &derr):
+		l.errorf(derr.Field, "%s", derr.Message)
+		return l.issues
+	case err != nil:
+		l.errorf("", "%s", err)
+		return l.issues
+	}
+
+	l.lint(conf)
+	sort.SliceStable(l.issues, func(a, b int) bool {
+		return l.issues[a].Field < l.issues[b].Field
+	})
+	return l.issues
+}
+
+func (l *linter) lint(conf *Config) {
+	if conf.TimeZone != "" {
+		if _, err := time.LoadLocation(conf.TimeZone); err != nil {
+			l.errorf("time_zone", "failed to load time zone: %s", err)
+		}
+	}
+
+	if hc := conf.HealthCheck; hc != nil {
+		listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", hc.Host, hc.Port))
+		if err != nil {
+			l.warnf("healthcheck.port", "the port is not available now (it may be used by the running worker): %s", err)
+		} else {
+			_ = listener.Close()
+		}
+	}
+
+	l.lintExclusion("exclude", conf.Exclude)
+
+	triggered := make(map[string]bool)
+	for _, e := range dependencyEdges(conf.Tasks) {
+		triggered[e.to] = true
+	}
+	for name, t := range conf.Tasks {
+		field := fmt.Sprintf("tasks.%s", name)
+		l.lintTask(field, name, t)
+		if !t.scheduled() && !triggered[name] {
+			l.warnf(field+".schedule", "the task without `schedule` is executed only by the API")
+		}
+	}
+}
+
+// lintExclusion checks the ranges and the calendars of the exclusion.
+// The events in the calendars which are ignored by the worker are reported as warnings.
+func (l *linter) lintExclusion(field string, e *Exclusion) {
+	if e == nil {
+		return
+	}
+	for i, r := range e.Ranges {
+		if _, _, err := r.period(); err != nil {
+			l.errorf(fmt.Sprintf("%s.ranges.%d", field, i), "%s", err)
+		}
+	}
+	for i, filename := range e.Calendars {
+		_, ignored, err := loadCalendar(filename, time.Local)
+		if err != nil {
+			l.errorf(fmt.Sprintf("%s.calendars.%d", field, i), "malformed calendar: %s", err)
+			continue
+		}
+		for _, msg := range ignored {
+			l.warnf(fmt.Sprintf("%s.calendars.%d", field, i), "%s", msg)
+		}
+	}
+}
+
+func (l *linter) lintTask(field string, name string, t *Task) {
+	l.lintExclusion(field+".exclude", t.Exclude)
+	if _, _, err := t.window(); err != nil {
+		l.errorf(field+".end_at", "%s", err)
+	} else if t.scheduled() {
+		if _, err := t.schedule(name, time.Local); err != nil {
+			l.errorf(field+".schedule", "malformed schedule: %s", err)
+		}
+	}
+	if t.windowState(time.Now()) == WindowStateExpired {
+		l.warnf(field, "the task is never executed by the scheduler since its active period has ended")
+	}
+
+	if t.Shell || t.Script != "" {
+		if _, err := exec.LookPath(t.executable()); err != nil {
+			l.warnf(field+".interpreter", "interpreter is not found: %s", err)
+		}
+	} else if t.Interpreter != "" {
+		l.warnf(field+".interpreter", "`interpreter` is ignored without `shell` or `script`")
+	} else if _, err := exec.LookPath(t.Command); err != nil {
+		l.warnf(field+".command", "command is not found: %s", err)
+	}
+
+	if t.WorkingDir != "" {
+		info, err := os.Stat(t.WorkingDir)
+		switch {
+		case err != nil:
+			l.errorf(field+".working_dir", "working directory is not available: %s", err)
+		case !info.IsDir():
+			l.errorf(field+".working_dir", "working directory is not a directory: %s", t.WorkingDir)
+		}
+	}
+	if t.User != "" || t.Group != "" {
+		if _, _, _, err := t.identity(); err != nil {
+			l.errorf(field+".user", "%s", err)
+		} else if os.Geteuid() > 0 {
+			l.warnf(field+".user", "Chronos worker must be privileged to execute the command as another user")
+		}
+	}
+	if t.RetryMaxWait > 0 && t.RetryMaxWait < t.RetryWait {
+		l.warnf(field+".retry_max_wait", "`retry_max_wait` is less than `retry_wait`")
+	}
+	for _, code := range t.RetryOnExitCodes {
+		if containsCode(t.NoRetryExitCodes, code) {
+			l.warnf(field+".retry_on_exit_codes", "exit code %d is in both of `retry_on_exit_codes` and `no_retry_exit_codes`", code)
+		}
+	}
+
+	templates := make(map[string]string, len(t.Args)+1)
+	for i, arg := range t.Args {
+		templates[fmt.Sprintf("%s.args.%d", field, i)] = arg
+	}
+	if t.Script != "" {
+		templates[field+".script"] = t.Script
+	}
+	tf := (&Job{name: name, task: t}).generateTemplateFuncMap(nil, 0)
+	for f, text := range templates {
+		if !t.UseTemplate {
+			if strings.Contains(text, "{{") {
+				l.warnf(f, "template is not applied without `use_template`")
+			}
+			continue
+		}
+		_, err := template.New("template").Funcs(tf).Parse(text)
+		if err != nil {
+			l.errorf(f, "malformed template: %s", err)
+		}
+	}
+
+	if t.Fallthrough && (t.FailureCount > 0 || t.FailureWindow > 0 || t.SuccessThreshold > 0) {
+		l.warnf(field+".failure_count", "`failure_count`, `failure_window` and `success_threshold` are ignored when `fallthrough` is enabled")
+	}
+	if t.RetryLimit == RetryLimitNever && t.RetryType == RetryTypeExponential {
+		l.warnf(field+".retry_type", "`retry_type` is ignored when `retry_limit` is 0 (never retry)")
+	}
+	if t.MaxCatchup > 0 && t.Catchup != CatchupPolicyAll {
+		l.warnf(field+".max_catchup", "`max_catchup` is ignored unless `catchup` is `all`")
+	}
+	if t.StartingDeadline > 0 && t.maxCatchup() == 0 {
+		l.warnf(field+".starting_deadline", "`starting_deadline` is ignored unless `catchup` is enabled")
+	}
+}
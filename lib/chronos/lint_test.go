@@ -0,0 +1,234 @@
+package chronos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xruins/chronos/lib/chronos"
+)
+
+func TestLintConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []*chronos.Issue
+	}{
+		{
+			name: "valid",
+			input: `
+tasks:
+  hello:
+    command: echo
+    args: ["{{count}}"]
+    use_template: true
+    schedule: "0 * * * * *"
+`,
+			want: nil,
+		},
+		{
+			name: "validation error",
+			input: `
+log_level: verbose
+tasks:
+  hello:
+    command: echo
+    schedule: "0 * * * * *"
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "log_level", Message: "failed on `oneof='fatal' 'error' 'warn' 'info' 'debug'|isdefault` validation. value: verbose"},
+			},
+		},
+		{
+			name: "runtime errors",
+			input: `
+tasks:
+  hello:
+    command: echo
+    args: ["{{count"]
+    use_template: true
+    schedule: "CRON_TZ=Unknown/Zone 0 * * * *"
+    fallthrough: true
+    failure_count: 2
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.hello.args.0", Message: "malformed template: template: template:1: unclosed action"},
+				{Severity: chronos.SeverityWarning, Field: "tasks.hello.failure_count", Message: "`failure_count`, `failure_window` and `success_threshold` are ignored when `fallthrough` is enabled"},
+				{Severity: chronos.SeverityError, Field: "tasks.hello.schedule", Message: "malformed schedule: failed to get timezone: unknown time zone Unknown/Zone"},
+			},
+		},
+		{
+			name: "malformed schedule",
+			input: `
+tasks:
+  hello:
+    command: echo
+    schedule: "0 9 32 * *"
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.hello.schedule", Message: "malformed schedule: malformed day of month: value 32 is out of range [1, 31]"},
+			},
+		},
+		{
+			name: "dependency cycle",
+			input: `
+tasks:
+  compress:
+    command: echo
+    depends_on: ["upload"]
+  upload:
+    command: echo
+    depends_on: ["compress"]
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.compress", Message: "dependencies have a cycle: compress -> upload -> compress"},
+			},
+		},
+		{
+			name: "run_once_at with active period",
+			input: `
+tasks:
+  migration:
+    command: echo
+    run_once_at: "2099-01-01T00:00:00Z"
+    end_at: "2099-12-31T00:00:00Z"
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.migration.run_once_at", Message: "failed on `excluded_with=Schedule StartAt EndAt` validation. value: 2099-01-01T00:00:00Z"},
+			},
+		},
+		{
+			name: "active period",
+			input: `
+tasks:
+  campaign:
+    command: echo
+    schedule: "0 * * * *"
+    start_at: "2024-06-30T00:00:00Z"
+    end_at: "2024-06-01T00:00:00Z"
+  migration:
+    command: echo
+    run_once_at: "2000-01-01T00:00:00Z"
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.campaign.end_at", Message: "`end_at` is before `start_at`: 2024-06-01T00:00:00Z < 2024-06-30T00:00:00Z"},
+				{Severity: chronos.SeverityWarning, Field: "tasks.migration", Message: "the task is never executed by the scheduler since its active period has ended"},
+			},
+		},
+		{
+			name: "malformed exclusion",
+			input: `
+tasks:
+  hello:
+    command: echo
+    schedule: "0 * * * *"
+    exclude:
+      schedules: ["@daily"]
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.hello.exclude.schedules.0", Message: "malformed exclusion: descriptors are not available for exclusion: @daily"},
+			},
+		},
+		{
+			name: "missing calendar",
+			input: `
+tasks:
+  hello:
+    command: echo
+    schedule: "0 * * * *"
+    exclude:
+      calendars: ["/nonexistent/holidays.ics"]
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.hello.exclude.calendars.0", Message: "malformed calendar: failed to open calendar: open /nonexistent/holidays.ics: no such file or directory"},
+			},
+		},
+		{
+			name: "malformed retry_on_stderr",
+			input: `
+tasks:
+  hello:
+    command: echo
+    retry_on_stderr: "("
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.hello.retry_on_stderr", Message: "malformed regular expression: error parsing regexp: missing closing ): `(`"},
+			},
+		},
+		{
+			name: "unknown kill signal",
+			input: `
+tasks:
+  hello:
+    command: echo
+    kill_signal: SIGFOO
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.hello.kill_signal", Message: "unknown signal: SIGFOO"},
+			},
+		},
+		{
+			name: "malformed umask",
+			input: `
+tasks:
+  hello:
+    command: echo
+    umask: "999"
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "tasks.hello.umask", Message: "malformed umask: 999"},
+			},
+		},
+		{
+			name: "exclusion range",
+			input: `
+exclude:
+  ranges:
+    - start: "2024-06-02T00:00:00Z"
+      end: "2024-06-01T00:00:00Z"
+tasks:
+  hello:
+    command: echo
+    schedule: "0 * * * *"
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityError, Field: "exclude.ranges.0", Message: "`end` is before `start`: 2024-06-01T00:00:00Z < 2024-06-02T00:00:00Z"},
+			},
+		},
+		{
+			name: "template without use_template",
+			input: `
+tasks:
+  hello:
+    command: echo
+    args: ["{{count}}"]
+    schedule: "0 * * * * *"
+`,
+			want: []*chronos.Issue{
+				{Severity: chronos.SeverityWarning, Field: "tasks.hello.args.0", Message: "template is not applied without `use_template`"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "config.yml")
+			err := os.WriteFile(filename, []byte(tt.input), 0o644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, i := range tt.want {
+				i.File = filename
+			}
+
+			got := chronos.LintConfig(filename)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected issues (-want +got):\n%s", diff)
+			}
+			if chronos.HasError(got) != chronos.HasError(tt.want) {
+				t.Errorf("unexpected result of HasError: %v", chronos.HasError(got))
+			}
+		})
+	}
+}
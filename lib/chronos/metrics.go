@@ -0,0 +1,191 @@
+package chronos
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsEndpoint is an endpoint to expose metrics in Prometheus format.
+	MetricsEndpoint = "/metrics"
+
+	metricsNamespace = "chronos"
+)
+
+// SkipReason is the enum of the reasons why a scheduled execution was skipped.
+type SkipReason string
+
+const (
+	// SkipReasonPaused is the reason for the execution skipped because the task is paused.
+	SkipReasonPaused SkipReason = "paused"
+	// SkipReasonConcurrency is the reason for the execution skipped by `ConcurrencyPolicy`.
+	SkipReasonConcurrency SkipReason = "concurrency"
+	// SkipReasonExcluded is the reason for the execution skipped by `Exclusion`.
+	SkipReasonExcluded SkipReason = "excluded"
+)
+
+// metrics is the collection of Prometheus metrics of Jobs.
+// All methods are no-op for nil receiver to make metrics optional for Jobs.
+type metrics struct {
+	runs      *prometheus.CounterVec
+	successes *prometheus.CounterVec
+	failures  *prometheus.CounterVec
+	retries   *prometheus.CounterVec
+	skipped   *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+}
+
+// newMetrics returns metrics registered to `registry`.
+// The gauges are collected from the Jobs returned by `jobs` on every scrape.
+// `next` and `health` return the time of the next run and the state of each Job.
+func newMetrics(registry prometheus.Registerer, jobs func() []*Job, next func(*Job) time.Time, health func(*Job) (State, string)) *metrics {
+	m := &metrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "task_runs_total",
+			Help:      "The number of runs of the task. Retries within a run are not counted.",
+		}, []string{"task", "trigger"}),
+		successes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "task_successes_total",
+			Help:      "The number of runs of the task which finished successfully.",
+		}, []string{"task"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "task_failures_total",
+			Help:      "The number of runs of the task which failed after retries.",
+		}, []string{"task"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "task_retries_total",
+			Help:      "The number of retries of the task.",
+		}, []string{"task"}),
+		skipped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "task_skipped_total",
+			Help:      "The number of executions of the task skipped without executing the command.",
+		}, []string{"task", "reason"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "task_execution_duration_seconds",
+			Help:      "The duration of each execution of the command of the task.",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600},
+		}, []string{"task", "status"}),
+	}
+
+	registry.MustRegister(m.runs, m.successes, m.failures, m.retries, m.skipped, m.duration)
+	registry.MustRegister(&jobCollector{jobs: jobs, next: next, health: health})
+	return m
+}
+
+func (m *metrics) runStarted(name string, trigger Trigger) {
+	if m == nil {
+		return
+	}
+	m.runs.WithLabelValues(name, string(trigger)).Inc()
+}
+
+func (m *metrics) executed(name string, e *Execution) {
+	if m == nil {
+		return
+	}
+	m.duration.WithLabelValues(name, string(e.Status)).Observe(e.Duration.Seconds())
+}
+
+func (m *metrics) retried(name string) {
+	if m == nil {
+		return
+	}
+	m.retries.WithLabelValues(name).Inc()
+}
+
+func (m *metrics) succeeded(name string) {
+	if m == nil {
+		return
+	}
+	m.successes.WithLabelValues(name).Inc()
+}
+
+func (m *metrics) failed(name string) {
+	if m == nil {
+		return
+	}
+	m.failures.WithLabelValues(name).Inc()
+}
+
+func (m *metrics) skip(name string, reason SkipReason) {
+	if m == nil {
+		return
+	}
+	m.skipped.WithLabelValues(name, string(reason)).Inc()
+}
+
+var (
+	runningDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "task_running"),
+		"The number of running executions of the task.",
+		[]string{"task"}, nil,
+	)
+	lastSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "task_last_success_timestamp_seconds"),
+		"The UNIX time when the last successful execution of the task finished.",
+		[]string{"task"}, nil,
+	)
+	nextRunDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "task_next_run_timestamp_seconds"),
+		"The UNIX time when the task is scheduled to be executed next.",
+		[]string{"task"}, nil,
+	)
+	stateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "task_state"),
+		"The state of the task. The value is 1 for the current state and 0 for the others.",
+		[]string{"task", "state"}, nil,
+	)
+	pausedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "task_paused"),
+		"1 if the task is paused, otherwise 0.",
+		[]string{"task"}, nil,
+	)
+)
+
+// jobCollector collects the gauges of Jobs on every scrape.
+type jobCollector struct {
+	jobs   func() []*Job
+	next   func(*Job) time.Time
+	health func(*Job) (State, string)
+}
+
+// Describe implements prometheus.Collector.
+func (c *jobCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- runningDesc
+	ch <- lastSuccessDesc
+	ch <- nextRunDesc
+	ch <- stateDesc
+	ch <- pausedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *jobCollector) Collect(ch chan<- prometheus.Metric) {
+	boolToFloat := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	for _, j := range c.jobs() {
+		ch <- prometheus.MustNewConstMetric(runningDesc, prometheus.GaugeValue, float64(j.Running()), j.name)
+		ch <- prometheus.MustNewConstMetric(pausedDesc, prometheus.GaugeValue, boolToFloat(j.IsPaused()), j.name)
+		if t := j.LastSuccess(); !t.IsZero() {
+			ch <- prometheus.MustNewConstMetric(lastSuccessDesc, prometheus.GaugeValue, float64(t.UnixNano())/1e9, j.name)
+		}
+		if t := c.next(j); !t.IsZero() {
+			ch <- prometheus.MustNewConstMetric(nextRunDesc, prometheus.GaugeValue, float64(t.UnixNano())/1e9, j.name)
+		}
+		state, _ := c.health(j)
+		for _, s := range []State{StateHealthy, StateUnhealthy} {
+			ch <- prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, boolToFloat(state == s), j.name, s.String())
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package chronos_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xruins/chronos/lib/chronos"
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestWorkerMetrics(t *testing.T) {
+	conf := &chronos.Config{
+		History: &chronos.History{Driver: chronos.HistoryDriverMemory},
+		Tasks: map[string]*chronos.Task{
+			"succeed": {
+				Command:   "true",
+				Schedule:  "@hourly",
+				RetryType: chronos.RetryTypeFixed,
+			},
+			"fail": {
+				Command:    "false",
+				Schedule:   "@hourly",
+				RetryLimit: 1,
+				RetryWait:  1,
+				RetryType:  chronos.RetryTypeFixed,
+			},
+		},
+	}
+	w, err := chronos.NewWorker(conf, &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to creare worker: %s", err)
+	}
+	w.Job("succeed").Run()
+	w.Job("fail").Run()
+	if err := w.Job("succeed").Pause(); err != nil {
+		t.Fatalf("failed to pause task: %s", err)
+	}
+	w.Job("succeed").Run()
+
+	server := httptest.NewServer(w.Handler())
+	defer server.Close()
+	res, err := server.Client().Get(server.URL + chronos.MetricsEndpoint)
+	if err != nil {
+		t.Fatalf("failed to request for metrics end-point: %s", err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %s", err)
+	}
+	body := string(b)
+
+	wants := []string{
+		`chronos_task_runs_total{task="succeed",trigger="schedule"} 1`,
+		`chronos_task_successes_total{task="succeed"} 1`,
+		`chronos_task_failures_total{task="fail"} 1`,
+		`chronos_task_retries_total{task="fail"} 1`,
+		`chronos_task_skipped_total{reason="paused",task="succeed"} 1`,
+		`chronos_task_execution_duration_seconds_count{status="failed",task="fail"} 2`,
+		`chronos_task_running{task="succeed"} 0`,
+		`chronos_task_paused{task="succeed"} 1`,
+		`chronos_task_state{state="unhealthy",task="fail"} 1`,
+		`chronos_task_state{state="healthy",task="succeed"} 1`,
+		`chronos_task_last_success_timestamp_seconds{task="succeed"}`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics does not contain `%s`. got: %s", want, body)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package chronos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xruins/chronos/lib/logger"
+)
+
+// NotificationEvent is the enum of the events to be notified.
+type NotificationEvent string
+
+const (
+	// NotificationEventFailure is the event that a task failed after retries.
+	NotificationEventFailure NotificationEvent = "failure"
+	// NotificationEventRecovery is the event that an unhealthy task became healthy again.
+	NotificationEventRecovery NotificationEvent = "recovery"
+	// NotificationEventSuccess is the event that a task succeeded.
+	NotificationEventSuccess NotificationEvent = "success"
+)
+
+const (
+	// DefaultWebhookTimeout is the seconds for timeout of a webhook request when `Webhook.Timeout` is not specified.
+	DefaultWebhookTimeout = 10
+	// DefaultWebhookRetryWait is the seconds to wait before retry of a webhook request when `Webhook.RetryWait` is not specified.
+	DefaultWebhookRetryWait = 5
+
+	// notificationOutputSize is the maximum bytes of STDOUT and STDERR in a notification.
+	notificationOutputSize = 4 * 1024
+)
+
+// defaultNotificationEvents are the events notified when `Webhook.Events` is not specified.
+var defaultNotificationEvents = []NotificationEvent{NotificationEventFailure, NotificationEventRecovery}
+
+// NotificationPayload is the JSON payload sent to webhooks.
+type NotificationPayload struct {
+	// Event is the kind of the event.
+	Event NotificationEvent `json:"event"`
+	// Task is the name of the task.
+	Task string `json:"task"`
+	// Command is the command of the task.
+	Command string `json:"command"`
+	// Args are the arguments of the command.
+	Args []string `json:"args"`
+	// Attempts is the number of attempts in the series of retry.
+	Attempts int `json:"attempts"`
+	// Error is the message of the error of the last execution.
+	Error string `json:"error,omitempty"`
+	// ExitCode is the exit code of the last execution.
+	ExitCode int `json:"exit_code"`
+	// Stdout is the tail of the output to STDOUT of the last execution.
+	Stdout string `json:"stdout,omitempty"`
+	// Stderr is the tail of the output to STDERR of the last execution.
+	Stderr string `json:"stderr,omitempty"`
+	// StartedAt is the time when the last execution started.
+	StartedAt time.Time `json:"started_at"`
+	// FinishedAt is the time when the last execution finished.
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// tail returns the tail of `s` which fits within `size` bytes.
+func tail(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+	return s[len(s)-size:]
+}
+
+// notifier sends notifications of the results of Jobs to webhooks.
+// All methods are no-op for nil receiver to make notifications optional for Jobs.
+type notifier struct {
+	client *http.Client
+	global func() *Notification
+	logger logger.Logger
+	wg     sync.WaitGroup
+}
+
+// newNotifier returns an instance of notifier.
+// `global` returns the global config applied to the tasks without their own config.
+func newNotifier(global func() *Notification, logger logger.Logger) *notifier {
+	return &notifier{
+		client: &http.Client{},
+		global: global,
+		logger: logger,
+	}
+}
+
+// notify sends the notification of the event to the webhooks which subscribe it asynchronously.
+func (n *notifier) notify(name string, task *Task, event NotificationEvent, e *Execution) {
+	if n == nil {
+		return
+	}
+	conf := task.Notification
+	if conf == nil {
+		conf = n.global()
+	}
+	if conf == nil {
+		return
+	}
+
+	payload := &NotificationPayload{
+		Event:      event,
+		Task:       name,
+		Command:    task.Command,
+		Args:       task.Args,
+		Attempts:   e.Attempt + 1,
+		Error:      e.Error,
+		ExitCode:   e.ExitCode,
+		Stdout:     tail(e.Stdout, notificationOutputSize),
+		Stderr:     tail(e.Stderr, notificationOutputSize),
+		StartedAt:  e.StartedAt,
+		FinishedAt: e.FinishedAt,
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		n.logger.Warnf("Task `%s` failed to marshal notification: %s", name, err)
+		return
+	}
+
+	for _, webhook := range conf.Webhooks {
+		if !webhook.subscribes(event) {
+			continue
+		}
+		n.wg.Add(1)
+		go func(webhook *Webhook) {
+			defer n.wg.Done()
+			err := n.send(webhook, b)
+			if err != nil {
+				n.logger.Warnf("Task `%s` failed to send %s notification to webhook: %s", name, event, err)
+			}
+		}(webhook)
+	}
+}
+
+// send posts the payload to the webhook with retry.
+func (n *notifier) send(webhook *Webhook, payload []byte) error {
+	timeout := time.Duration(webhook.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = DefaultWebhookTimeout * time.Second
+	}
+	retryWait := time.Duration(webhook.RetryWait) * time.Second
+	if retryWait == 0 {
+		retryWait = DefaultWebhookRetryWait * time.Second
+	}
+
+	var err error
+	for i := 0; i <= webhook.RetryLimit; i++ {
+		if i > 0 {
+			time.Sleep(retryWait)
+		}
+		err = n.post(webhook, payload, timeout)
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("gave up after %d attempt(s): %w", webhook.RetryLimit+1, err)
+}
+
+func (n *notifier) post(webhook *Webhook, payload []byte, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to exec a request: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_, _ = io.Copy(io.Discard, Body)
+		_ = Body.Close()
+	}(res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// wait waits for the notifications in process until `ctx` is done.
+func (n *notifier) wait(ctx context.Context) {
+	if n == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		n.logger.Warnf("gave up waiting for notifications to be sent.")
+	}
+}
+
+// subscribes returns true if the webhook should be notified of the event.
+func (w *Webhook) subscribes(event NotificationEvent) bool {
+	events := w.Events
+	if len(events) == 0 {
+		events = defaultNotificationEvents
+	}
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package chronos_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xruins/chronos/lib/chronos"
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestWorkerNotification(t *testing.T) {
+	payloads := make(chan *chronos.NotificationPayload, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Token"); got != "token" {
+			t.Errorf("unexpected header. got: %s", got)
+		}
+		p := &chronos.NotificationPayload{}
+		err := json.NewDecoder(r.Body).Decode(p)
+		if err != nil {
+			t.Errorf("failed to decode payload: %s", err)
+		}
+		payloads <- p
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	flag := filepath.Join(t.TempDir(), "flag")
+	conf := &chronos.Config{
+		History: &chronos.History{Driver: chronos.HistoryDriverMemory},
+		Notification: &chronos.Notification{
+			Webhooks: []*chronos.Webhook{
+				{
+					URL:     server.URL,
+					Events:  []chronos.NotificationEvent{chronos.NotificationEventFailure, chronos.NotificationEventRecovery},
+					Headers: map[string]string{"X-Token": "token"},
+				},
+			},
+		},
+		Tasks: map[string]*chronos.Task{
+			"flaky": {
+				Command:   "sh",
+				Args:      []string{"-c", "echo checking; test -f " + flag},
+				Schedule:  "@hourly",
+				RetryType: chronos.RetryTypeFixed,
+			},
+		},
+	}
+	w, err := chronos.NewWorker(conf, &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to creare worker: %s", err)
+	}
+
+	receive := func() *chronos.NotificationPayload {
+		select {
+		case p := <-payloads:
+			return p
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for notification")
+		}
+		return nil
+	}
+
+	w.Job("flaky").Run()
+	got := receive()
+	want := &chronos.NotificationPayload{
+		Event:    chronos.NotificationEventFailure,
+		Task:     "flaky",
+		Command:  "sh",
+		Args:     conf.Tasks["flaky"].Args,
+		Attempts: 1,
+		Error:    "exit status 1",
+		ExitCode: 1,
+		Stdout:   "checking\n",
+	}
+	opt := cmp.FilterPath(func(p cmp.Path) bool {
+		name := p.Last().String()
+		return name == ".StartedAt" || name == ".FinishedAt"
+	}, cmp.Ignore())
+	if diff := cmp.Diff(want, got, opt); diff != "" {
+		t.Errorf("unexpected payload. diff: %s", diff)
+	}
+
+	err = os.WriteFile(flag, nil, 0o644)
+	if err != nil {
+		t.Fatalf("failed to create flag: %s", err)
+	}
+	w.Job("flaky").Run()
+	if got := receive(); got.Event != chronos.NotificationEventRecovery {
+		t.Errorf("unexpected event. got: %s, want: %s", got.Event, chronos.NotificationEventRecovery)
+	}
+
+	// success is not subscribed
+	w.Job("flaky").Run()
+	select {
+	case p := <-payloads:
+		t.Errorf("unexpected notification. got: %+v", p)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
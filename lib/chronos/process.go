@@ -0,0 +1,28 @@
+package chronos
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// parseUmask parses the umask in octal.
+func parseUmask(s string) (int, error) {
+	mask, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mask > 0o777 {
+		return 0, fmt.Errorf("malformed umask: %s", s)
+	}
+	return int(mask), nil
+}
+
+// DefaultKillGracePeriod is the seconds to wait for the processes to exit after `Task.KillSignal` is sent
+// when `Task.KillGracePeriod` is not specified.
+const DefaultKillGracePeriod = 10
+
+// killGracePeriod returns the duration to wait for the processes to exit after `KillSignal` is sent.
+func (t *Task) killGracePeriod() time.Duration {
+	if t.KillGracePeriod > 0 {
+		return time.Duration(t.KillGracePeriod) * time.Second
+	}
+	return DefaultKillGracePeriod * time.Second
+}
@@ -0,0 +1,48 @@
+//go:build !unix
+
+package chronos
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// identity returns an error if `User` or `Group` is specified since they are not supported on this platform.
+func (t *Task) identity() (uid uint32, gid uint32, groups []uint32, err error) {
+	if t.User != "" || t.Group != "" {
+		return 0, 0, nil, errors.New("`user` and `group` are not supported on this platform")
+	}
+	return 0, 0, nil, nil
+}
+
+// killSignal returns an error unless `KillSignal` is `SIGKILL`, since the other signals are not supported on this platform.
+func (t *Task) killSignal() (string, error) {
+	if t.KillSignal != "" && strings.TrimPrefix(strings.ToUpper(t.KillSignal), "SIG") != "KILL" {
+		return "", errors.New("`kill_signal` other than SIGKILL is not supported on this platform")
+	}
+	return "SIGKILL", nil
+}
+
+// prepareCommand applies the working directory of the task to `cmd`.
+// `User`, `Group` and `Umask` are not supported on this platform.
+func (t *Task) prepareCommand(cmd *exec.Cmd) error {
+	if _, _, _, err := t.identity(); err != nil {
+		return err
+	}
+	if t.Umask != "" {
+		return errors.New("`umask` is not supported on this platform")
+	}
+	cmd.Dir = t.WorkingDir
+	cmd.WaitDelay = t.killGracePeriod()
+	return nil
+}
+
+// chownScript does nothing since `User` and `Group` are not supported on this platform.
+func (t *Task) chownScript(string) error {
+	return nil
+}
+
+// reap does nothing since process groups are not supported on this platform.
+func (t *Task) reap(*exec.Cmd, time.Time) {}
@@ -0,0 +1,216 @@
+//go:build unix
+
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// reapInterval is the interval to check if the processes in the process group exited.
+const reapInterval = 100 * time.Millisecond
+
+// lookupUser returns the user of the name or the ID.
+// The ID which is not in the user database is also accepted.
+func lookupUser(s string) (uid uint32, gid uint32, groups []uint32, err error) {
+	u, err := user.Lookup(s)
+	var unknown user.UnknownUserError
+	if errors.As(err, &unknown) {
+		u, err = user.LookupId(s)
+	}
+	if err != nil {
+		id, perr := strconv.ParseUint(s, 10, 32)
+		if perr != nil {
+			return 0, 0, nil, fmt.Errorf("failed to look up user: %w", err)
+		}
+		return uint32(id), uint32(id), nil, nil
+	}
+
+	id, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("malformed uid of user %s: %w", s, err)
+	}
+	primary, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("malformed gid of user %s: %w", s, err)
+	}
+	gids, err := u.GroupIds()
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to look up groups of user %s: %w", s, err)
+	}
+	for _, g := range gids {
+		gid, err := strconv.ParseUint(g, 10, 32)
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("malformed gid of user %s: %w", s, err)
+		}
+		groups = append(groups, uint32(gid))
+	}
+	return uint32(id), uint32(primary), groups, nil
+}
+
+// lookupGroup returns the ID of the group of the name or the ID.
+// The ID which is not in the group database is also accepted.
+func lookupGroup(s string) (uint32, error) {
+	g, err := user.LookupGroup(s)
+	var unknown user.UnknownGroupError
+	if errors.As(err, &unknown) {
+		g, err = user.LookupGroupId(s)
+	}
+	if err != nil {
+		id, perr := strconv.ParseUint(s, 10, 32)
+		if perr != nil {
+			return 0, fmt.Errorf("failed to look up group: %w", err)
+		}
+		return uint32(id), nil
+	}
+	id, err := strconv.ParseUint(g.Gid, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("malformed gid of group %s: %w", s, err)
+	}
+	return uint32(id), nil
+}
+
+// identity returns the IDs of the user, the group and the supplementary groups specified by `User` and `Group`.
+func (t *Task) identity() (uid uint32, gid uint32, groups []uint32, err error) {
+	uid, gid = uint32(os.Geteuid()), uint32(os.Getegid())
+	if t.User != "" {
+		uid, gid, groups, err = lookupUser(t.User)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	if t.Group != "" {
+		gid, err = lookupGroup(t.Group)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		groups = []uint32{gid}
+	}
+	return uid, gid, groups, nil
+}
+
+// credential returns the credential to execute the command as `User` and `Group`.
+// It returns nil if the command should be executed as Chronos worker itself.
+func (t *Task) credential() (*syscall.Credential, error) {
+	if t.User == "" && t.Group == "" {
+		return nil, nil
+	}
+	uid, gid, groups, err := t.identity()
+	if err != nil {
+		return nil, err
+	}
+
+	if os.Geteuid() != 0 {
+		if uid != uint32(os.Geteuid()) || gid != uint32(os.Getegid()) {
+			return nil, fmt.Errorf("the worker is not privileged to execute the command as uid %d and gid %d", uid, gid)
+		}
+		return nil, nil
+	}
+	return &syscall.Credential{Uid: uid, Gid: gid, Groups: groups}, nil
+}
+
+// chownScript changes the owner of the script file to `User` and `Group` to let the command read it.
+func (t *Task) chownScript(path string) error {
+	cred, err := t.credential()
+	if err != nil || cred == nil {
+		return err
+	}
+	err = os.Chown(path, int(cred.Uid), int(cred.Gid))
+	if err != nil {
+		return fmt.Errorf("failed to change owner of script file: %w", err)
+	}
+	return nil
+}
+
+// signals are the signals available for `KillSignal`.
+var signals = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+// killSignal returns the signal specified by `KillSignal`.
+// Both of `SIGTERM` and `TERM` are accepted.
+func (t *Task) killSignal() (syscall.Signal, error) {
+	if t.KillSignal == "" {
+		return syscall.SIGTERM, nil
+	}
+	name := strings.ToUpper(t.KillSignal)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	sig, ok := signals[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown signal: %s", t.KillSignal)
+	}
+	return sig, nil
+}
+
+// umaskShell is the shell to apply `Umask` in the child process before executing the command.
+const umaskShell = "/bin/sh"
+
+// applyUmask rewrites `cmd` to be executed via the shell which sets `Umask`.
+// The umask of Chronos worker is shared by all goroutines, so it is never changed.
+func (t *Task) applyUmask(cmd *exec.Cmd) error {
+	if t.Umask == "" {
+		return nil
+	}
+	mask, err := parseUmask(t.Umask)
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf(`umask %03o && exec "$@"`, mask)
+	cmd.Args = append([]string{umaskShell, "-c", script, "chronos", cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = umaskShell
+	return nil
+}
+
+// prepareCommand applies the working directory, the credential and the umask of the task to `cmd`.
+// The command is started in its own process group, which receives `KillSignal` when `cmd` is cancelled.
+func (t *Task) prepareCommand(cmd *exec.Cmd) error {
+	cmd.Dir = t.WorkingDir
+	cred, err := t.credential()
+	if err != nil {
+		return err
+	}
+	sig, err := t.killSignal()
+	if err != nil {
+		return err
+	}
+	if err := t.applyUmask(cmd); err != nil {
+		return err
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Credential: cred}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, sig)
+	}
+	// do not wait for the descendants holding STDOUT and STDERR over the grace period
+	cmd.WaitDelay = t.killGracePeriod()
+	return nil
+}
+
+// reap waits for the processes left in the process group of the cancelled command to exit,
+// and kills them by SIGKILL if they are still running at `deadline`.
+func (t *Task) reap(cmd *exec.Cmd, deadline time.Time) {
+	pgid := cmd.Process.Pid
+	for time.Now().Before(deadline) {
+		// signal 0 checks the existence of the processes in the group
+		if err := syscall.Kill(-pgid, 0); errors.Is(err, syscall.ESRCH) {
+			return
+		}
+		time.Sleep(reapInterval)
+	}
+	_ = syscall.Kill(-pgid, syscall.SIGKILL)
+}
@@ -0,0 +1,205 @@
+//go:build unix
+
+package chronos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestJobExecuteProcessIdentity(t *testing.T) {
+	dir := t.TempDir()
+	// allow other users to create files in the directory
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		task *Task
+		root bool
+		want string
+	}{
+		{
+			name: "working directory",
+			task: &Task{Command: "pwd", WorkingDir: dir},
+			want: dir + "\n",
+		},
+		{
+			name: "umask",
+			task: &Task{Command: "sh", Args: []string{"-c", "umask"}, Umask: "027"},
+			want: "0027\n",
+		},
+		{
+			name: "umask with arguments",
+			task: &Task{Command: "sh", Args: []string{"-c", `umask; echo "$0" "$1"`, "a b", "c"}, Umask: "077"},
+			want: "0077\na b c\n",
+		},
+		{
+			name: "user and group",
+			task: &Task{Command: "id", Args: []string{"-u"}, User: "65534", Group: "65534"},
+			root: true,
+			want: "65534\n",
+		},
+		{
+			name: "script with user",
+			task: &Task{Script: "id -u", User: "65534", Group: "65534"},
+			root: true,
+			want: "65534\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.root && os.Geteuid() != 0 {
+				t.Skip("requires root privilege")
+			}
+			j := NewJob(tt.name, tt.task, NewMemoryHistoryStore(), &logger.NopLogger{})
+			e, err := j.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("failed to execute: %s", err)
+			}
+			if e.Stdout != tt.want {
+				t.Errorf("unexpected output. got: %q, want: %q", e.Stdout, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown user", func(t *testing.T) {
+		task := &Task{Command: "true", User: "chronos-unknown-user"}
+		j := NewJob("unknown", task, NewMemoryHistoryStore(), &logger.NopLogger{})
+		if _, err := j.Execute(context.Background()); err == nil {
+			t.Errorf("expected error for unknown user")
+		}
+	})
+
+	t.Run("umask of the worker is not changed", func(t *testing.T) {
+		before := syscall.Umask(0o022)
+		syscall.Umask(before)
+
+		task := &Task{Command: "true", Umask: "077"}
+		j := NewJob("umask", task, NewMemoryHistoryStore(), &logger.NopLogger{})
+		if _, err := j.Execute(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		after := syscall.Umask(before)
+		if after != before {
+			t.Errorf("umask of the worker must not be changed. got: %o, want: %o", after, before)
+		}
+	})
+}
+
+func TestJobExecuteTimeout(t *testing.T) {
+	// alive returns true if the process is running. Zombie processes are regarded as exited.
+	alive := func(pid int) bool {
+		if err := syscall.Kill(pid, 0); err != nil {
+			return false
+		}
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err != nil {
+			return true
+		}
+		fields := strings.Fields(string(stat))
+		return len(fields) < 3 || fields[2] != "Z"
+	}
+
+	t.Run("kill process group", func(t *testing.T) {
+		task := &Task{
+			Command:         "sh",
+			Args:            []string{"-c", "sleep 30 >/dev/null 2>&1 & echo $!; wait"},
+			Timeout:         1,
+			KillSignal:      "KILL",
+			KillGracePeriod: 1,
+		}
+		j := NewJob("timeout", task, NewMemoryHistoryStore(), &logger.NopLogger{})
+		e, err := j.Execute(context.Background())
+		if !errors.Is(err, errTimedOut) {
+			t.Fatalf("expected timeout error. got: %v", err)
+		}
+		if e.Status != ExecutionStatusTimedOut {
+			t.Errorf("unexpected status. got: %s, want: %s", e.Status, ExecutionStatusTimedOut)
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(e.Stdout))
+		if err != nil {
+			t.Fatalf("malformed pid: %s", e.Stdout)
+		}
+		if alive(pid) {
+			_ = syscall.Kill(pid, syscall.SIGKILL)
+			t.Errorf("the grandchild process must be killed")
+		}
+	})
+
+	t.Run("kill signal", func(t *testing.T) {
+		task := &Task{
+			Command:         "sh",
+			Args:            []string{"-c", "trap 'echo terminated; exit 1' TERM; sleep 30 & wait"},
+			Timeout:         1,
+			KillGracePeriod: 5,
+		}
+		j := NewJob("signal", task, NewMemoryHistoryStore(), &logger.NopLogger{})
+		started := time.Now()
+		e, _ := j.Execute(context.Background())
+		if e.Status != ExecutionStatusTimedOut || e.Stdout != "terminated\n" {
+			t.Errorf("the command must be terminated by SIGTERM. got: %+v", e)
+		}
+		if elapsed := time.Since(started); elapsed > 4*time.Second {
+			t.Errorf("the command must exit without waiting for the grace period. elapsed: %s", elapsed)
+		}
+	})
+
+	t.Run("exit successfully after signal", func(t *testing.T) {
+		tests := []struct {
+			name string
+			task *Task
+		}{
+			{
+				name: "exit 0",
+				task: &Task{Command: "sh", Args: []string{"-c", "trap 'exit 0' TERM; sleep 30 & wait"}, Timeout: 1},
+			},
+			{
+				name: "success exit code",
+				task: &Task{Command: "sh", Args: []string{"-c", "trap 'exit 3' TERM; sleep 30 & wait"}, Timeout: 1, SuccessExitCodes: []int{3}},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				j := NewJob("signal", tt.task, NewMemoryHistoryStore(), &logger.NopLogger{})
+				e, err := j.Execute(context.Background())
+				if !errors.Is(err, errTimedOut) {
+					t.Errorf("expected timeout error. got: %v", err)
+				}
+				if e.Status != ExecutionStatusTimedOut {
+					t.Errorf("unexpected status. got: %s, want: %s", e.Status, ExecutionStatusTimedOut)
+				}
+			})
+		}
+	})
+
+	t.Run("grace period from the signal", func(t *testing.T) {
+		task := &Task{
+			Command:         "sh",
+			Args:            []string{"-c", "trap '' TERM; sleep 30 & wait"},
+			Timeout:         1,
+			KillGracePeriod: 2,
+		}
+		j := NewJob("ignore", task, NewMemoryHistoryStore(), &logger.NopLogger{})
+		started := time.Now()
+		e, _ := j.Execute(context.Background())
+		if e.Status != ExecutionStatusTimedOut {
+			t.Errorf("unexpected status. got: %s, want: %s", e.Status, ExecutionStatusTimedOut)
+		}
+		if elapsed := time.Since(started); elapsed > 4500*time.Millisecond {
+			t.Errorf("the command must be killed after the grace period from the signal. elapsed: %s", elapsed)
+		}
+	})
+}
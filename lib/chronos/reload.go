@@ -0,0 +1,164 @@
+package chronos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reload applies `conf` to the Worker without stopping running executions.
+// Added tasks are registered, removed tasks are unregistered and changed tasks are re-scheduled.
+// Executions in progress keep running with the previous settings until they finish,
+// and the runs of changed tasks are still subject to their `ConcurrencyPolicy`.
+// If `conf` is invalid, it returns error and the current config is kept.
+//
+// The settings of HealthCheck server (except for `Token`) and the history store
+// are not reloaded. They require restart of the Worker.
+func (w *Worker) Reload(conf *Config) error {
+	if err := validateConfig(conf); err != nil {
+		return err
+	}
+	loc, err := loadLocation(conf.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]*Job)
+	for _, j := range w.Jobs() {
+		current[j.name] = j
+	}
+
+	var added, changed, removed []string
+	jobs := make([]*Job, 0, len(conf.Tasks))
+	for name, t := range conf.Tasks {
+		old, ok := current[name]
+		if ok && reflect.DeepEqual(old.task, t) {
+			jobs = append(jobs, old)
+			continue
+		}
+
+		j, err := w.newJob(name, t)
+		if err != nil {
+			return err
+		}
+		if ok {
+			// take over the health, the in-flight runs and the progress of `DependsOn` of the task
+			old.mu.RLock()
+			j.State, j.health, j.since = old.State, old.health, old.since
+			for _, upstream := range t.DependsOn {
+				if old.satisfied[upstream] {
+					if j.satisfied == nil {
+						j.satisfied = make(map[string]bool)
+					}
+					j.satisfied[upstream] = true
+				}
+			}
+			old.mu.RUnlock()
+			j.runs = old.runs
+			changed = append(changed, name)
+		} else {
+			added = append(added, name)
+		}
+		jobs = append(jobs, j)
+	}
+	for name := range current {
+		if _, ok := conf.Tasks[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	c, err := w.newCron(jobs, loc)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if w.closing {
+		w.mu.Unlock()
+		return errors.New("worker is shutting down")
+	}
+	prev := w.conf
+	w.conf = conf
+	w.jobs = jobs
+	w.loc = loc
+	if w.cron != nil {
+		w.cron.Stop()
+		w.cron = c
+		c.Start()
+	}
+	w.mu.Unlock()
+
+	if !reflect.DeepEqual(withoutToken(prev.HealthCheck), withoutToken(conf.HealthCheck)) {
+		w.logger.Warnf("The change of `healthcheck` requires restart of the worker to take effect except for `token`.")
+	}
+	if !reflect.DeepEqual(prev.History, conf.History) {
+		w.logger.Warnf("The change of `history` requires restart of the worker to take effect.")
+	}
+	if prev.LogLevel != conf.LogLevel {
+		w.logger.Warnf("The change of `log_level` requires restart of the worker to take effect.")
+	}
+
+	sort.Strings(added)
+	sort.Strings(changed)
+	sort.Strings(removed)
+	w.logger.Infof("Config has been reloaded. added: [%s], changed: [%s], removed: [%s]",
+		strings.Join(added, ", "), strings.Join(changed, ", "), strings.Join(removed, ", "))
+	return nil
+}
+
+// withoutToken returns a copy of the config whose token is cleared.
+func withoutToken(conf *HealthCheck) *HealthCheck {
+	if conf == nil {
+		return nil
+	}
+	c := *conf
+	c.Token = ""
+	return &c
+}
+
+// ReloadFile reads the config file and applies it to the Worker by `Reload`.
+func (w *Worker) ReloadFile(filename string) error {
+	conf, err := LoadConfig(filename)
+	if err != nil {
+		return err
+	}
+	err = w.Reload(conf)
+	if err != nil {
+		return fmt.Errorf("failed to apply config: %w", err)
+	}
+	return nil
+}
+
+// WatchFile polls the file on every `interval` and invokes `onChange` when its modification time or size changed.
+// It blocks until `ctx` is done.
+func WatchFile(ctx context.Context, filename string, interval time.Duration, onChange func()) {
+	stat := func() (time.Time, int64) {
+		fi, err := os.Stat(filename)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return fi.ModTime(), fi.Size()
+	}
+
+	modTime, size := stat()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m, s := stat()
+			if s < 0 || (m.Equal(modTime) && s == size) {
+				continue
+			}
+			modTime, size = m, s
+			onChange()
+		}
+	}
+}
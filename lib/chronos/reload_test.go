@@ -0,0 +1,200 @@
+package chronos_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xruins/chronos/lib/chronos"
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func newReloadTestConfig(tasks map[string]string) *chronos.Config {
+	conf := &chronos.Config{
+		History: &chronos.History{Driver: chronos.HistoryDriverMemory},
+		Tasks:   make(map[string]*chronos.Task),
+	}
+	for name, schedule := range tasks {
+		conf.Tasks[name] = &chronos.Task{
+			Command:   "echo",
+			Args:      []string{name},
+			Schedule:  schedule,
+			RetryType: chronos.RetryTypeFixed,
+		}
+	}
+	return conf
+}
+
+func jobNames(w *chronos.Worker) []string {
+	var names []string
+	for _, j := range w.Jobs() {
+		names = append(names, j.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWorkerReload(t *testing.T) {
+	w, err := chronos.NewWorker(newReloadTestConfig(map[string]string{
+		"unchanged": "@hourly",
+		"changed":   "@hourly",
+		"removed":   "@hourly",
+	}), &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to creare worker: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = w.Run(ctx)
+	}()
+
+	unchanged := w.Job("unchanged")
+	unchanged.Run()
+
+	err = w.Reload(newReloadTestConfig(map[string]string{
+		"unchanged": "@hourly",
+		"changed":   "@invalid",
+	}))
+	if err == nil {
+		t.Errorf("expected error for invalid config")
+	}
+	if diff := cmp.Diff([]string{"changed", "removed", "unchanged"}, jobNames(w)); diff != "" {
+		t.Errorf("the current config must be kept on error. diff: %s", diff)
+	}
+
+	err = w.Reload(newReloadTestConfig(map[string]string{
+		"unchanged": "@hourly",
+		"changed":   "@daily",
+		"added":     "@daily",
+	}))
+	if err != nil {
+		t.Fatalf("failed to reload config: %s", err)
+	}
+	if diff := cmp.Diff([]string{"added", "changed", "unchanged"}, jobNames(w)); diff != "" {
+		t.Errorf("unexpected tasks after reload. diff: %s", diff)
+	}
+	if w.Job("unchanged") != unchanged {
+		t.Errorf("the Job for unchanged task must be kept")
+	}
+	executions, err := w.Job("unchanged").History(0)
+	if err != nil {
+		t.Fatalf("failed to get history: %s", err)
+	}
+	if len(executions) != 1 {
+		t.Errorf("the history of unchanged task must be kept. got: %+v", executions)
+	}
+}
+
+func TestWorkerReloadValidation(t *testing.T) {
+	w, err := chronos.NewWorker(newReloadTestConfig(map[string]string{"task": "@hourly"}), &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to creare worker: %s", err)
+	}
+
+	j := w.Job("task")
+	tests := []struct {
+		name   string
+		modify func(conf *chronos.Config)
+	}{
+		{
+			name:   "missing command",
+			modify: func(conf *chronos.Config) { conf.Tasks["task"].Command = "" },
+		},
+		{
+			name:   "unknown dependency",
+			modify: func(conf *chronos.Config) { conf.Tasks["task"].DependsOn = []string{"unknown"} },
+		},
+		{
+			name:   "malformed umask",
+			modify: func(conf *chronos.Config) { conf.Tasks["task"].Umask = "999" },
+		},
+		{
+			name:   "unknown kill signal",
+			modify: func(conf *chronos.Config) { conf.Tasks["task"].KillSignal = "SIGFOO" },
+		},
+		{
+			name:   "malformed retry_on_stderr",
+			modify: func(conf *chronos.Config) { conf.Tasks["task"].RetryOnStderr = "(" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := newReloadTestConfig(map[string]string{"task": "@daily"})
+			tt.modify(conf)
+			if err := w.Reload(conf); err == nil {
+				t.Errorf("expected error for invalid config")
+			}
+			if w.Job("task") != j {
+				t.Errorf("the current config must be kept on error")
+			}
+		})
+	}
+}
+
+func TestWorkerReloadKeepsRuns(t *testing.T) {
+	conf := newReloadTestConfig(map[string]string{"task": ""})
+	conf.Tasks["task"].Command = "sleep"
+	conf.Tasks["task"].Args = []string{"1"}
+	conf.Tasks["task"].ConcurrencyPolicy = chronos.ConcurrencyPolicyForbid
+	w, err := chronos.NewWorker(conf, &logger.NopLogger{})
+	if err != nil {
+		t.Fatalf("failed to creare worker: %s", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Job("task").Trigger(context.Background())
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for w.Job("task").Running() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	changed := newReloadTestConfig(map[string]string{"task": ""})
+	changed.Tasks["task"].Command = "true"
+	changed.Tasks["task"].ConcurrencyPolicy = chronos.ConcurrencyPolicyForbid
+	if err := w.Reload(changed); err != nil {
+		t.Fatalf("failed to reload config: %s", err)
+	}
+	if got := w.Job("task").Running(); got != 1 {
+		t.Errorf("the in-flight run must be taken over. got: %d", got)
+	}
+	if e := w.Job("task").Trigger(context.Background()); e.Status != chronos.ExecutionStatusSkipped {
+		t.Errorf("the run must be forbidden while the previous run is in progress. got: %s", e.Status)
+	}
+	<-done
+}
+
+func TestWatchFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	err := os.WriteFile(filename, []byte("foo"), 0o644)
+	if err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	go chronos.WatchFile(ctx, filename, 10*time.Millisecond, func() {
+		changed <- struct{}{}
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	err = os.WriteFile(filename, []byte("foobar"), 0o644)
+	if err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		t.Fatal("change of the file was not detected")
+	}
+}
@@ -0,0 +1,175 @@
+package chronos
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// stderrPatterns are the compiled expressions of `RetryOnStderr`, so that they are compiled only once.
+var stderrPatterns sync.Map
+
+// stderrPattern returns the compiled expression of `RetryOnStderr`.
+func (t *Task) stderrPattern() (*regexp.Regexp, error) {
+	if p, ok := stderrPatterns.Load(t.RetryOnStderr); ok {
+		return p.(*regexp.Regexp), nil
+	}
+	pattern, err := regexp.Compile(t.RetryOnStderr)
+	if err != nil {
+		return nil, err
+	}
+	stderrPatterns.Store(t.RetryOnStderr, pattern)
+	return pattern, nil
+}
+
+// containsCode returns true if `codes` contain `code`.
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isSuccessExitCode returns true if the exit code is regarded as success.
+func (t *Task) isSuccessExitCode(code int) bool {
+	return code == 0 || containsCode(t.SuccessExitCodes, code)
+}
+
+// retryable returns whether the failed execution should be retried, with the reason if not.
+// The executions which failed without exit code (e.g. timeout) are retried unless `RetryOnExitCodes` or `RetryOnStderr` is specified.
+func (t *Task) retryable(e *Execution) (bool, string, error) {
+	if containsCode(t.NoRetryExitCodes, e.ExitCode) {
+		return false, fmt.Sprintf("exit code %d is not retryable", e.ExitCode), nil
+	}
+	if len(t.RetryOnExitCodes) == 0 && t.RetryOnStderr == "" {
+		return true, "", nil
+	}
+
+	if containsCode(t.RetryOnExitCodes, e.ExitCode) {
+		return true, "", nil
+	}
+	if t.RetryOnStderr != "" {
+		pattern, err := t.stderrPattern()
+		if err != nil {
+			return false, "", fmt.Errorf("malformed retry_on_stderr: %w", err)
+		}
+		if pattern.MatchString(e.Stderr) {
+			return true, "", nil
+		}
+	}
+	return false, fmt.Sprintf("exit code %d and STDERR do not match the retry conditions", e.ExitCode), nil
+}
+
+// backoff computes the waits before retries of a task.
+type backoff struct {
+	task    *Task
+	retries int
+	prev    time.Duration
+	// random returns a random duration in [0, n).
+	random func(n int64) int64
+}
+
+// newBackoff returns the backoff for a series of retry of the task.
+func newBackoff(task *Task) *backoff {
+	return &backoff{
+		task:   task,
+		random: rand.Int63n,
+	}
+}
+
+// between returns a random duration in [min, max].
+func (b *backoff) between(min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	n := int64(max - min)
+	if n < math.MaxInt64 {
+		n++
+	}
+	return min + time.Duration(b.random(n))
+}
+
+// next returns the wait before the next retry.
+func (b *backoff) next() time.Duration {
+	base := time.Duration(b.task.RetryWait) * time.Second
+	maxWait := time.Duration(b.task.RetryMaxWait) * time.Second
+	if maxWait == 0 {
+		maxWait = math.MaxInt64
+	}
+
+	var wait time.Duration
+	switch b.task.RetryType {
+	case RetryTypeLinear:
+		wait = base * time.Duration(b.retries+1)
+		if base != 0 && wait/base != time.Duration(b.retries+1) {
+			wait = maxWait
+		}
+	case RetryTypeExponential:
+		// avoid overflow on many retries
+		f := float64(base) * math.Pow(2, float64(b.retries))
+		if f >= float64(maxWait) {
+			wait = maxWait
+		} else {
+			wait = time.Duration(f)
+		}
+	default:
+		wait = base
+	}
+	if wait > maxWait {
+		wait = maxWait
+	}
+	b.retries++
+
+	switch b.task.RetryJitter {
+	case RetryJitterFull:
+		wait = b.between(0, wait)
+	case RetryJitterEqual:
+		wait = b.between(wait/2, wait)
+	case RetryJitterDecorrelated:
+		prev := b.prev
+		if prev < base {
+			prev = base
+		}
+		upper := prev * 3
+		if upper/3 != prev || upper > maxWait {
+			upper = maxWait
+		}
+		wait = b.between(base, upper)
+		if wait > maxWait {
+			wait = maxWait
+		}
+	}
+	b.prev = wait
+	return wait
+}
+
+// retry decides whether to retry after the failure of the `attempt`-th attempt, which starts with 0.
+// `elapsed` is the time since the first attempt started.
+// It returns the wait before the retry, or the reason not to retry.
+func (b *backoff) retry(attempt int, e *Execution, elapsed time.Duration) (time.Duration, string, bool) {
+	switch limit := b.task.RetryLimit; {
+	case limit == RetryLimitNever:
+		return 0, "retry is disabled", false
+	case limit > 0 && attempt >= int(limit):
+		return 0, fmt.Sprintf("exceeded the retry limit of %d", limit), false
+	}
+
+	ok, reason, err := b.task.retryable(e)
+	if err != nil {
+		return 0, err.Error(), false
+	}
+	if !ok {
+		return 0, reason, false
+	}
+
+	wait := b.next()
+	if deadline := time.Duration(b.task.RetryDeadline) * time.Second; deadline > 0 && elapsed+wait > deadline {
+		return 0, fmt.Sprintf("the retry would exceed the deadline of %d seconds", b.task.RetryDeadline), false
+	}
+	return wait, "", true
+}
@@ -0,0 +1,294 @@
+package chronos
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestTaskRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		task      *Task
+		execution *Execution
+		want      bool
+	}{
+		{
+			name:      "retry all failures by default",
+			task:      &Task{},
+			execution: &Execution{ExitCode: 1},
+			want:      true,
+		},
+		{
+			name:      "no retry exit codes",
+			task:      &Task{NoRetryExitCodes: []int{2}},
+			execution: &Execution{ExitCode: 2},
+			want:      false,
+		},
+		{
+			name:      "retry on exit codes",
+			task:      &Task{RetryOnExitCodes: []int{75}},
+			execution: &Execution{ExitCode: 75},
+			want:      true,
+		},
+		{
+			name:      "not in retry on exit codes",
+			task:      &Task{RetryOnExitCodes: []int{75}},
+			execution: &Execution{ExitCode: 1},
+			want:      false,
+		},
+		{
+			name:      "retry on stderr",
+			task:      &Task{RetryOnExitCodes: []int{75}, RetryOnStderr: "connection (refused|reset)"},
+			execution: &Execution{ExitCode: 1, Stderr: "error: connection refused\n"},
+			want:      true,
+		},
+		{
+			name:      "stderr does not match",
+			task:      &Task{RetryOnStderr: "connection (refused|reset)"},
+			execution: &Execution{ExitCode: 1, Stderr: "error: permission denied\n"},
+			want:      false,
+		},
+		{
+			name:      "no retry exit codes take precedence",
+			task:      &Task{NoRetryExitCodes: []int{1}, RetryOnStderr: "refused"},
+			execution: &Execution{ExitCode: 1, Stderr: "connection refused"},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.task.retryable(tt.execution)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected result. got: %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		task         *Task
+		wantStatus   ExecutionStatus
+		wantAttempts int
+	}{
+		{
+			name:         "success exit code",
+			task:         &Task{Command: "sh", Args: []string{"-c", "exit 3"}, SuccessExitCodes: []int{3}},
+			wantStatus:   ExecutionStatusSucceeded,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retried exit code",
+			task:         &Task{Command: "sh", Args: []string{"-c", "exit 75"}, RetryLimit: 1, RetryWait: 0, RetryOnExitCodes: []int{75}},
+			wantStatus:   ExecutionStatusFailed,
+			wantAttempts: 2,
+		},
+		{
+			name:         "not retried exit code",
+			task:         &Task{Command: "sh", Args: []string{"-c", "exit 1"}, RetryLimit: 1, RetryWait: 0, RetryOnExitCodes: []int{75}},
+			wantStatus:   ExecutionStatusFailed,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := NewMemoryHistoryStore()
+			j := NewJob(tt.name, tt.task, history, &logger.NopLogger{})
+			e := j.Trigger(context.Background())
+			if e.Status != tt.wantStatus {
+				t.Errorf("unexpected status. got: %s, want: %s", e.Status, tt.wantStatus)
+			}
+			executions, err := history.List(tt.name, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(executions) != tt.wantAttempts {
+				t.Errorf("unexpected number of attempts. got: %d, want: %d", len(executions), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Task
+		// random returns a random duration in [0, n).
+		random func(n int64) int64
+		want   []time.Duration
+	}{
+		{
+			name: "fixed",
+			task: &Task{RetryType: RetryTypeFixed, RetryWait: 5},
+			want: []time.Duration{5 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			name: "linear",
+			task: &Task{RetryType: RetryTypeLinear, RetryWait: 5},
+			want: []time.Duration{5 * time.Second, 10 * time.Second, 15 * time.Second},
+		},
+		{
+			name: "exponential",
+			task: &Task{RetryType: RetryTypeExponential, RetryWait: 5},
+			want: []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second},
+		},
+		{
+			name: "max wait",
+			task: &Task{RetryType: RetryTypeExponential, RetryWait: 5, RetryMaxWait: 15},
+			want: []time.Duration{5 * time.Second, 10 * time.Second, 15 * time.Second, 15 * time.Second},
+		},
+		{
+			name:   "full jitter",
+			task:   &Task{RetryType: RetryTypeExponential, RetryWait: 4, RetryJitter: RetryJitterFull},
+			random: func(n int64) int64 { return n - 1 },
+			want:   []time.Duration{4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:   "equal jitter",
+			task:   &Task{RetryType: RetryTypeExponential, RetryWait: 4, RetryJitter: RetryJitterEqual},
+			random: func(int64) int64 { return 0 },
+			want:   []time.Duration{2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:   "decorrelated jitter",
+			task:   &Task{RetryWait: 4, RetryMaxWait: 100, RetryJitter: RetryJitterDecorrelated},
+			random: func(n int64) int64 { return n - 1 },
+			want:   []time.Duration{12 * time.Second, 36 * time.Second, 100 * time.Second},
+		},
+		{
+			name: "no overflow",
+			task: &Task{RetryType: RetryTypeExponential, RetryWait: 1, RetryJitter: RetryJitterFull},
+			random: func(n int64) int64 {
+				if n <= 0 {
+					t.Fatalf("invalid argument for random: %d", n)
+				}
+				return n - 1
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBackoff(tt.task)
+			if tt.random != nil {
+				b.random = tt.random
+			}
+			if tt.want == nil {
+				for i := 0; i < 100; i++ {
+					if wait := b.next(); wait < 0 {
+						t.Fatalf("negative wait: %s", wait)
+					}
+				}
+				return
+			}
+			for i, want := range tt.want {
+				if got := b.next(); got != want {
+					t.Errorf("unexpected wait of retry %d. got: %s, want: %s", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffRetry(t *testing.T) {
+	failure := &Execution{ExitCode: 1}
+	tests := []struct {
+		name    string
+		task    *Task
+		attempt int
+		elapsed time.Duration
+		want    bool
+	}{
+		{
+			name: "never retry by default",
+			task: &Task{},
+			want: false,
+		},
+		{
+			name:    "within retry limit",
+			task:    &Task{RetryLimit: 2},
+			attempt: 1,
+			want:    true,
+		},
+		{
+			name:    "exceeded retry limit",
+			task:    &Task{RetryLimit: 2},
+			attempt: 2,
+			want:    false,
+		},
+		{
+			name:    "infinite retry",
+			task:    &Task{RetryLimit: RetryLimitInfinite},
+			attempt: 1000,
+			want:    true,
+		},
+		{
+			name:    "within retry deadline",
+			task:    &Task{RetryLimit: RetryLimitInfinite, RetryWait: 10, RetryDeadline: 60},
+			elapsed: 50 * time.Second,
+			want:    true,
+		},
+		{
+			name:    "exceeded retry deadline",
+			task:    &Task{RetryLimit: RetryLimitInfinite, RetryWait: 10, RetryDeadline: 60},
+			elapsed: 51 * time.Second,
+			want:    false,
+		},
+		{
+			name: "not retryable",
+			task: &Task{RetryLimit: RetryLimitInfinite, NoRetryExitCodes: []int{1}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, reason, got := newBackoff(tt.task).retry(tt.attempt, failure, tt.elapsed)
+			if got != tt.want {
+				t.Errorf("unexpected decision. got: %v (%s), want: %v", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobRunAttempts(t *testing.T) {
+	history := NewMemoryHistoryStore()
+	task := &Task{
+		Command:     "sh",
+		Args:        []string{"-c", "echo {{attempt}}; exit 1"},
+		UseTemplate: true,
+		RetryLimit:  2,
+	}
+	j := NewJob("attempts", task, history, &logger.NopLogger{})
+	e := j.Trigger(context.Background())
+	if e.Attempt != 2 {
+		t.Errorf("unexpected attempt of the last execution. got: %d, want: 2", e.Attempt)
+	}
+
+	executions, err := history.List("attempts", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(executions) != 3 {
+		t.Fatalf("unexpected number of executions. got: %d, want: 3", len(executions))
+	}
+	for i, e := range executions {
+		if e.Attempt != i {
+			t.Errorf("unexpected attempt. got: %d, want: %d", e.Attempt, i)
+		}
+		if want := fmt.Sprintf("%d\n", i+1); e.Stdout != want {
+			t.Errorf("unexpected output of {{attempt}}. got: %q, want: %q", e.Stdout, want)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package chronos
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// scheduleFieldRanges are the ranges of the fields of schedules.
+// (Seconds, Minutes, Hours, Day of month, Month, Day of week)
+var scheduleFieldRanges = [][2]int{
+	{0, 59},
+	{0, 59},
+	{0, 23},
+	{1, 31},
+	{1, 12},
+	{0, 6},
+}
+
+// hashFieldRanges are the ranges of `H` without explicit range for the fields of schedules.
+// Day of month is limited to 1-28, so that the task is executed in every month.
+var hashFieldRanges = [][2]int{
+	{0, 59},
+	{0, 59},
+	{0, 23},
+	{1, 28},
+	{1, 12},
+	{0, 6},
+}
+
+// hashOf returns the stable hash of the task name for the field at `index`.
+func hashOf(name string, index int) int {
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%s/%d", name, index)
+	return int(h.Sum32() & 0x7fffffff)
+}
+
+// expandHashField expands `H` in the field of the schedule into the stable value for the task.
+// The following forms are supported.
+// `H`: a value in the range of the field. (e.g. `H` in minutes to `37`) Day of month is limited to 1-28.
+// `H(a-b)`: a value between `a` and `b`. (e.g. `H(0-29)` in minutes to `17`)
+// `H/n`: every `n` from a stable offset less than `n`. (e.g. `H/15` in minutes to `7-59/15`)
+// `H(a-b)/n`: every `n` between `a` and `b` from a stable offset.
+func expandHashField(field string, name string, index int) (string, error) {
+	if !strings.HasPrefix(field, "H") {
+		return field, nil
+	}
+	min, max := hashFieldRanges[index][0], hashFieldRanges[index][1]
+	rest := field[1:]
+
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+		if end < 0 {
+			return "", fmt.Errorf("malformed hash: %s", field)
+		}
+		lo, hi, ok := strings.Cut(rest[1:end], "-")
+		if !ok {
+			return "", fmt.Errorf("malformed range of hash: %s", field)
+		}
+		var err error
+		if min, err = strconv.Atoi(lo); err != nil {
+			return "", fmt.Errorf("malformed range of hash: %s", field)
+		}
+		if max, err = strconv.Atoi(hi); err != nil {
+			return "", fmt.Errorf("malformed range of hash: %s", field)
+		}
+		if min > max || min < scheduleFieldRanges[index][0] || max > scheduleFieldRanges[index][1] {
+			return "", fmt.Errorf("range of hash is out of bounds: %s", field)
+		}
+		rest = rest[end+1:]
+	}
+
+	h := hashOf(name, index)
+	switch {
+	case rest == "":
+		return strconv.Itoa(min + h%(max-min+1)), nil
+	case strings.HasPrefix(rest, "/"):
+		step, err := strconv.Atoi(rest[1:])
+		if err != nil || step <= 0 {
+			return "", fmt.Errorf("malformed step of hash: %s", field)
+		}
+		offset := h % step
+		if min+offset > max {
+			offset = h % (max - min + 1)
+		}
+		return fmt.Sprintf("%d-%d/%d", min+offset, max, step), nil
+	default:
+		return "", fmt.Errorf("malformed hash: %s", field)
+	}
+}
+
+// expandHash expands `H` in the schedule into the stable values for the task,
+// so that the tasks of the same schedule are spread over the period.
+// (e.g. `0 H * * * *` runs hourly at a stable minute for each task)
+func expandHash(spec string, name string) (string, error) {
+	if strings.HasPrefix(spec, "@") || !strings.Contains(spec, "H") {
+		return spec, nil
+	}
+
+	fields := strings.Fields(spec)
+	// the fields without Seconds start from Minutes
+	offset := len(scheduleFieldRanges) - len(fields)
+	if offset != 0 && offset != 1 {
+		return "", fmt.Errorf("expected 5 or 6 fields, found %d: %s", len(fields), spec)
+	}
+	for i, f := range fields {
+		expanded, err := expandHashField(f, name, i+offset)
+		if err != nil {
+			return "", err
+		}
+		fields[i] = expanded
+	}
+	return strings.Join(fields, " "), nil
+}
+
+// parseSchedule parses the schedule of the task.
+// In addition to the syntax of parseCronSpec, `H` is available to spread the tasks. See `expandHash`.
+func parseSchedule(spec string, name string) (cron.Schedule, error) {
+	expanded, err := expandHash(spec, name)
+	if err != nil {
+		return nil, err
+	}
+	return parseCronSpec(expanded)
+}
+
+// validateSchedule returns error if the schedule is malformed.
+// The time zone prefix is not verified since it depends on the environment.
+func validateSchedule(spec string) error {
+	_, rest, _ := cutTimeZone(spec)
+	_, err := parseSchedule(rest, "")
+	return err
+}
+
+// splay returns a random delay of the scheduled execution up to `Splay` seconds.
+func (t *Task) splay() time.Duration {
+	if t.Splay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(t.Splay) * int64(time.Second)))
+}
+
+// timeZonePrefixes are the prefixes of schedules to specify the time zone. (e.g. `CRON_TZ=Asia/Tokyo 0 0 9 * * *`)
+var timeZonePrefixes = []string{"CRON_TZ=", "TZ="}
+
+// cutTimeZone separates the time zone prefix from the schedule.
+func cutTimeZone(spec string) (tz string, rest string, ok bool) {
+	for _, prefix := range timeZonePrefixes {
+		if strings.HasPrefix(spec, prefix) {
+			tz, rest, _ = strings.Cut(strings.TrimPrefix(spec, prefix), " ")
+			return tz, strings.TrimSpace(rest), true
+		}
+	}
+	return "", spec, false
+}
+
+// schedule returns the schedule of the task, which fires in the time zone of the task.
+// The time zone is `CRON_TZ=` prefix of `Schedule` or `TimeZone`, and `loc` is used if neither is specified.
+// It fires only in the active period of the task, or only once at `RunOnceAt`.
+func (t *Task) schedule(name string, loc *time.Location) (cron.Schedule, error) {
+	loc, spec, err := t.zone(loc)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := t.window()
+	if err != nil {
+		return nil, err
+	}
+	if t.RunOnceAt != "" {
+		return &onceSchedule{at: start}, nil
+	}
+
+	schedule, err := parseSchedule(spec, name)
+	if err != nil {
+		return nil, err
+	}
+	schedule = &zonedSchedule{schedule: schedule, loc: loc}
+	if !start.IsZero() || !end.IsZero() {
+		schedule = &windowSchedule{schedule: schedule, start: start, end: end}
+	}
+	return schedule, nil
+}
+
+// zone returns the time zone of the task and `Schedule` without the time zone prefix.
+// The time zone is `CRON_TZ=` prefix of `Schedule` or `TimeZone`, and `loc` is returned if neither is specified.
+func (t *Task) zone(loc *time.Location) (*time.Location, string, error) {
+	spec := t.Schedule
+	tz := t.TimeZone
+	if prefix, rest, ok := cutTimeZone(spec); ok {
+		if tz != "" {
+			return nil, "", fmt.Errorf("time zone is specified by both of `time_zone` and the schedule: %s", spec)
+		}
+		tz, spec = prefix, rest
+	}
+	if tz == "" {
+		return loc, spec, nil
+	}
+	zone, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get timezone: %w", err)
+	}
+	return zone, spec, nil
+}
+
+// zonedSchedule is the schedule which fires in the time zone regardless of the one of the scheduler.
+// It handles the transitions of daylight saving time like Vixie cron for the schedules which do not fire every hour.
+// The times skipped by the transition (e.g. 02:30 when the clock jumps from 02:00 to 03:00) fire at the transition,
+// and the times repeated by the transition (e.g. 01:30 when the clock goes back from 02:00 to 01:00) fire only once.
+type zonedSchedule struct {
+	schedule cron.Schedule
+	loc      *time.Location
+}
+
+// Next returns the next time to fire after `t`.
+func (s *zonedSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc)
+	next := s.schedule.Next(t)
+	if next.IsZero() || !s.adjustsTransitions() {
+		return next
+	}
+
+	if transition, ok := s.skipped(t, next); ok {
+		return transition
+	}
+	if s.repeated(next) {
+		return s.Next(next)
+	}
+	return next
+}
+
+// adjustsTransitions returns true if the schedule should be adjusted on the transitions of daylight saving time.
+// The schedules which fire every hour are not adjusted since they follow the elapsed time rather than the wall clock.
+func (s *zonedSchedule) adjustsTransitions() bool {
+	spec, ok := s.schedule.(*specSchedule)
+	if !ok {
+		return false
+	}
+	const everyHour = 1<<24 - 1
+	return spec.hour&everyHour != everyHour
+}
+
+// skipped returns the transition between `t` and `next` if the schedule fires at the time skipped by it.
+func (s *zonedSchedule) skipped(t time.Time, next time.Time) (time.Time, bool) {
+	_, end := t.ZoneBounds()
+	for !end.IsZero() && !end.After(next) {
+		transition := end
+		_, before := transition.Add(-time.Second).Zone()
+		_, after := transition.Zone()
+		if after > before {
+			// evaluate the schedule with the offset before the transition, in which the skipped times exist
+			fixed := time.FixedZone("", before)
+			candidate := s.schedule.Next(transition.Add(-time.Second).In(fixed))
+			if candidate.Before(transition.Add(time.Duration(after-before)*time.Second)) && transition.After(t) {
+				return transition, true
+			}
+		}
+		_, end = transition.ZoneBounds()
+	}
+	return time.Time{}, false
+}
+
+// repeated returns true if the wall clock of `t` has already appeared before the last transition.
+func (s *zonedSchedule) repeated(t time.Time) bool {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+	_, before := start.Add(-time.Second).Zone()
+	_, after := t.Zone()
+	if before <= after {
+		return false
+	}
+	return t.Before(start.Add(time.Duration(before-after) * time.Second))
+}
@@ -0,0 +1,276 @@
+package chronos
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		check   func(fields []string) bool
+		wantErr bool
+	}{
+		{
+			name:  "without hash",
+			spec:  "0 30 * * * *",
+			check: func(fields []string) bool { return strings.Join(fields, " ") == "0 30 * * * *" },
+		},
+		{
+			name:  "descriptor",
+			spec:  "@hourly",
+			check: func(fields []string) bool { return fields[0] == "@hourly" },
+		},
+		{
+			name: "hash",
+			spec: "0 H H * * *",
+			check: func(fields []string) bool {
+				minute, err1 := strconv.Atoi(fields[1])
+				hour, err2 := strconv.Atoi(fields[2])
+				return err1 == nil && err2 == nil && minute >= 0 && minute <= 59 && hour >= 0 && hour <= 23
+			},
+		},
+		{
+			name: "hash with range",
+			spec: "0 H(10-14) * * * *",
+			check: func(fields []string) bool {
+				minute, err := strconv.Atoi(fields[1])
+				return err == nil && minute >= 10 && minute <= 14
+			},
+		},
+		{
+			name: "hash with step",
+			spec: "0 H/15 * * * *",
+			check: func(fields []string) bool {
+				from, rest, ok := strings.Cut(fields[1], "-")
+				offset, err := strconv.Atoi(from)
+				return ok && err == nil && offset < 15 && rest == "59/15"
+			},
+		},
+		{
+			name: "hash in day of month",
+			spec: "0 0 0 H * *",
+			check: func(fields []string) bool {
+				day, err := strconv.Atoi(fields[3])
+				return err == nil && day >= 1 && day <= 28
+			},
+		},
+		{
+			name: "hash with range in day of month",
+			spec: "0 0 0 H(29-31) * *",
+			check: func(fields []string) bool {
+				day, err := strconv.Atoi(fields[3])
+				return err == nil && day >= 29 && day <= 31
+			},
+		},
+		{
+			name:    "range out of bounds",
+			spec:    "0 0 H(0-30) * * *",
+			wantErr: true,
+		},
+		{
+			name:    "malformed hash",
+			spec:    "0 Hx * * * *",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandHash(tt.spec, "task")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !tt.check(strings.Fields(got)) {
+				t.Errorf("unexpected expansion: %s", got)
+			}
+			if _, err := parseSchedule(tt.spec, "task"); err != nil {
+				t.Errorf("failed to parse expanded schedule: %s", err)
+			}
+
+			again, _ := expandHash(tt.spec, "task")
+			if again != got {
+				t.Errorf("expansion must be stable. got: %s, then: %s", got, again)
+			}
+		})
+	}
+}
+
+func TestExpandHashDayOfMonth(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		name := "task" + strconv.Itoa(i)
+		for _, spec := range []string{"0 0 0 H * *", "0 0 0 H/7 * *"} {
+			expanded, err := expandHash(spec, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			field := strings.Fields(expanded)[3]
+			from, to, _ := strings.Cut(strings.Split(field, "/")[0], "-")
+			for _, v := range []string{from, to} {
+				if day, err := strconv.Atoi(v); v != "" && (err != nil || day < 1 || day > 28) {
+					t.Fatalf("`H` in day of month must be within 1-28. got: %s for %s", expanded, name)
+				}
+			}
+		}
+	}
+}
+
+func TestExpandHashSpreadsTasks(t *testing.T) {
+	minutes := make(map[string]struct{})
+	for i := 0; i < 20; i++ {
+		spec, err := expandHash("0 H * * * *", "task"+strconv.Itoa(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		minutes[spec] = struct{}{}
+	}
+	if len(minutes) < 10 {
+		t.Errorf("tasks must be spread. got only %d distinct schedules", len(minutes))
+	}
+}
+
+func TestTaskSplay(t *testing.T) {
+	if got := (&Task{}).splay(); got != 0 {
+		t.Errorf("splay must be 0 by default. got: %s", got)
+	}
+	task := &Task{Splay: 2}
+	for i := 0; i < 100; i++ {
+		if got := task.splay(); got < 0 || got >= 2*time.Second {
+			t.Fatalf("splay out of range: %s", got)
+		}
+	}
+}
+
+func TestTaskScheduleTimeZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		task    *Task
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "scheduler time zone",
+			task: &Task{Schedule: "0 0 9 * * *"},
+			want: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "time zone of task",
+			task: &Task{Schedule: "0 0 9 * * *", TimeZone: "Asia/Tokyo"},
+			want: time.Date(2024, 1, 2, 9, 0, 0, 0, tokyo),
+		},
+		{
+			name: "time zone prefix",
+			task: &Task{Schedule: "CRON_TZ=Asia/Tokyo 0 0 9 * * *"},
+			want: time.Date(2024, 1, 2, 9, 0, 0, 0, tokyo),
+		},
+		{
+			name:    "both of time zone and prefix",
+			task:    &Task{Schedule: "TZ=Asia/Tokyo 0 0 9 * * *", TimeZone: "UTC"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown time zone",
+			task:    &Task{Schedule: "0 0 9 * * *", TimeZone: "Unknown/Zone"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := tt.task.schedule("task", time.UTC)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := schedule.Next(now); !got.Equal(tt.want) {
+				t.Errorf("unexpected next time. got: %s, want: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskScheduleDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// `at` returns the time of the wall clock in New York with the offset in hours.
+	at := func(year int, month time.Month, day, hour, min, offset int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.FixedZone("", offset*3600))
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		from     time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "skipped time fires at the transition",
+			schedule: "0 30 2 * * *",
+			from:     at(2024, 3, 9, 12, 0, -5),
+			want: []time.Time{
+				at(2024, 3, 10, 3, 0, -4),
+				at(2024, 3, 11, 2, 30, -4),
+			},
+		},
+		{
+			name:     "repeated time fires once",
+			schedule: "0 30 1 * * *",
+			from:     at(2024, 11, 2, 12, 0, -4),
+			want: []time.Time{
+				at(2024, 11, 3, 1, 30, -4),
+				at(2024, 11, 4, 1, 30, -5),
+			},
+		},
+		{
+			name:     "hourly schedule follows the elapsed time on the repeated hour",
+			schedule: "0 0 * * * *",
+			from:     at(2024, 11, 3, 0, 30, -4),
+			want: []time.Time{
+				at(2024, 11, 3, 1, 0, -4),
+				at(2024, 11, 3, 1, 0, -5),
+				at(2024, 11, 3, 2, 0, -5),
+			},
+		},
+		{
+			name:     "hourly schedule follows the elapsed time on the skipped hour",
+			schedule: "0 30 * * * *",
+			from:     at(2024, 3, 10, 1, 0, -5),
+			want: []time.Time{
+				at(2024, 3, 10, 1, 30, -5),
+				at(2024, 3, 10, 3, 30, -4),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := (&Task{Schedule: tt.schedule}).schedule("task", ny)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := tt.from
+			for i, want := range tt.want {
+				next = schedule.Next(next)
+				if !next.Equal(want) {
+					t.Errorf("unexpected time of fire %d. got: %s, want: %s", i, next, want)
+				}
+			}
+		})
+	}
+}
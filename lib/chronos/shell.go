@@ -0,0 +1,66 @@
+package chronos
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultInterpreter is the interpreter for `Task.Shell` and `Task.Script` when `Task.Interpreter` is not specified.
+const DefaultInterpreter = "/bin/sh -c"
+
+// interpreter returns the fields of the command line of the interpreter.
+func (t *Task) interpreter() []string {
+	fields := strings.Fields(t.Interpreter)
+	if len(fields) == 0 {
+		fields = strings.Fields(DefaultInterpreter)
+	}
+	return fields
+}
+
+// executable returns the name of the executable to be executed for the task.
+func (t *Task) executable() string {
+	if t.Shell || t.Script != "" {
+		return t.interpreter()[0]
+	}
+	return t.Command
+}
+
+// commandLine returns the name and the arguments of the process to execute the task.
+// `name` is given to the shell as `$0`, and `script` is the path to the file of `Task.Script`.
+func (t *Task) commandLine(name string, args []string, script string) (string, []string) {
+	switch {
+	case t.Script != "":
+		interpreter := t.interpreter()
+		if last := len(interpreter) - 1; last > 0 && interpreter[last] == "-c" {
+			interpreter = interpreter[:last]
+		}
+		return interpreter[0], append(append(interpreter[1:], script), args...)
+	case t.Shell:
+		interpreter := t.interpreter()
+		return interpreter[0], append(append(interpreter[1:], t.Command, name), args...)
+	default:
+		return t.Command, args
+	}
+}
+
+// writeScript writes the script into a temporary file and returns the path to it.
+// The caller must remove the file after the execution.
+func writeScript(script string) (string, error) {
+	f, err := os.CreateTemp("", "chronos-script-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create script file: %w", err)
+	}
+	_, err = f.WriteString(script)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write script file: %w", err)
+	}
+	err = f.Close()
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write script file: %w", err)
+	}
+	return f.Name(), nil
+}
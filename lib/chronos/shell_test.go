@@ -0,0 +1,91 @@
+package chronos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xruins/chronos/lib/logger"
+)
+
+func TestTaskCommandLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     *Task
+		wantName string
+		wantArgs []string
+	}{
+		{
+			name:     "command",
+			task:     &Task{Command: "echo", Args: []string{"hello"}},
+			wantName: "echo",
+			wantArgs: []string{"hello"},
+		},
+		{
+			name:     "shell",
+			task:     &Task{Command: "echo $1 | tr a-z A-Z", Args: []string{"hello"}, Shell: true},
+			wantName: "/bin/sh",
+			wantArgs: []string{"-c", "echo $1 | tr a-z A-Z", "test", "hello"},
+		},
+		{
+			name:     "shell with interpreter",
+			task:     &Task{Command: "false | true", Shell: true, Interpreter: "bash -euo pipefail -c"},
+			wantName: "bash",
+			wantArgs: []string{"-euo", "pipefail", "-c", "false | true", "test"},
+		},
+		{
+			name:     "script",
+			task:     &Task{Script: "echo hello", Args: []string{"hello"}, Interpreter: "bash -eu -c"},
+			wantName: "bash",
+			wantArgs: []string{"-eu", "/tmp/script", "hello"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotArgs := tt.task.commandLine("test", tt.task.Args, "/tmp/script")
+			if gotName != tt.wantName {
+				t.Errorf("unexpected name. got: %s, want: %s", gotName, tt.wantName)
+			}
+			if diff := cmp.Diff(tt.wantArgs, gotArgs); diff != "" {
+				t.Errorf("unexpected args (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJobExecuteShell(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Task
+		want string
+	}{
+		{
+			name: "shell",
+			task: &Task{Command: "echo $1 | tr a-z A-Z", Args: []string{"hello"}, Shell: true},
+			want: "HELLO\n",
+		},
+		{
+			name: "script",
+			task: &Task{
+				Script:      "for s in \"$@\"; do\n  echo \"{{name}}: $s\"\ndone\n",
+				Args:        []string{"hello", "world"},
+				UseTemplate: true,
+			},
+			want: "script: hello\nscript: world\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := NewJob(tt.name, tt.task, NewMemoryHistoryStore(), &logger.NopLogger{})
+			e, err := j.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("failed to execute: %s", err)
+			}
+			if e.Stdout != tt.want {
+				t.Errorf("unexpected output. got: %q, want: %q", e.Stdout, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+package chronos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// descriptors are the predefined schedules available with `@`.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// RebootDescriptor is the schedule to execute the task once when the worker started.
+const RebootDescriptor = "@reboot"
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	weekdayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
+// bounds is the range of the values of a field.
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondBounds  = bounds{0, 59, nil}
+	minuteBounds  = bounds{0, 59, nil}
+	hourBounds    = bounds{0, 23, nil}
+	domBounds     = bounds{1, 31, nil}
+	monthBounds   = bounds{1, 12, monthNames}
+	weekdayBounds = bounds{0, 7, weekdayNames}
+)
+
+// specSchedule is the schedule specified by a cron expression.
+type specSchedule struct {
+	second, minute, hour, month uint64
+	dom                         domSpec
+	dow                         dowSpec
+}
+
+// domSpec is the field of day of month.
+type domSpec struct {
+	bits uint64
+	// star is true if the field is `*` or `?`.
+	star bool
+	// lastOffsets are the days before the last day of month. (`L` for 0, `L-3` for 3)
+	lastOffsets []int
+	// nearest are the days whose nearest weekdays match. (`15W`)
+	nearest []int
+	// lastWeekday is true if the last weekday of month matches. (`LW`)
+	lastWeekday bool
+}
+
+// dowSpec is the field of day of week.
+type dowSpec struct {
+	bits uint64
+	// star is true if the field is `*` or `?`.
+	star bool
+	// last are the weekdays whose last occurrence in month matches. (`5L` for the last Friday)
+	last []int
+	// nth are the weekdays and their occurrences in month which match. (`2#2` for the second Tuesday)
+	nth [][2]int
+}
+
+// rebootSchedule is the schedule of `@reboot`, which is never fired by the scheduler.
+// The tasks of `@reboot` are executed once by `Worker.Run`.
+type rebootSchedule struct{}
+
+// Next returns zero value not to be fired by the scheduler.
+func (rebootSchedule) Next(time.Time) time.Time {
+	return time.Time{}
+}
+
+// isReboot returns true if the schedule is `@reboot`.
+func isReboot(s cron.Schedule) bool {
+	for {
+		switch v := s.(type) {
+		case rebootSchedule:
+			return true
+		case *windowSchedule:
+			s = v.schedule
+		case *zonedSchedule:
+			s = v.schedule
+		default:
+			return false
+		}
+	}
+}
+
+// parseCronSpec parses the cron expression in 5 fields (Minutes, Hours, Day of month, Month, Day of week)
+// or 6 fields with Seconds at the beginning, and the descriptors such as `@daily`, `@every 1h30m` and `@reboot`.
+// In addition to the standard syntax, the following extensions are supported.
+// Day of month: `L` (the last day), `L-3` (3 days before the last day), `15W` (the nearest weekday to the 15th), `LW` (the last weekday).
+// Day of week: `5L` (the last Friday), `2#2` (the second Tuesday).
+// If both of day of month and day of week are restricted, the day matching either of them fires like the standard cron.
+func parseCronSpec(spec string) (cron.Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		switch {
+		case spec == RebootDescriptor:
+			return rebootSchedule{}, nil
+		case strings.HasPrefix(spec, "@every "):
+			d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse duration of %s: %w", spec, err)
+			}
+			if d < time.Second {
+				return nil, fmt.Errorf("interval of %s must be 1 second or longer", spec)
+			}
+			return cron.Every(d), nil
+		}
+		expanded, ok := descriptors[spec]
+		if !ok {
+			return nil, fmt.Errorf("unknown descriptor: %s", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 fields, found %d: %s", len(fields), spec)
+	}
+
+	s := &specSchedule{}
+	var err error
+	if s.second, _, err = parseField(fields[0], secondBounds); err != nil {
+		return nil, fmt.Errorf("malformed seconds: %w", err)
+	}
+	if s.minute, _, err = parseField(fields[1], minuteBounds); err != nil {
+		return nil, fmt.Errorf("malformed minutes: %w", err)
+	}
+	if s.hour, _, err = parseField(fields[2], hourBounds); err != nil {
+		return nil, fmt.Errorf("malformed hours: %w", err)
+	}
+	if s.dom, err = parseDomField(fields[3]); err != nil {
+		return nil, fmt.Errorf("malformed day of month: %w", err)
+	}
+	if s.month, _, err = parseField(fields[4], monthBounds); err != nil {
+		return nil, fmt.Errorf("malformed month: %w", err)
+	}
+	if s.dow, err = parseDowField(fields[5]); err != nil {
+		return nil, fmt.Errorf("malformed day of week: %w", err)
+	}
+
+	if s.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("schedule never fires: %s", spec)
+	}
+	return s, nil
+}
+
+// parseValue parses a number or a name of the field.
+func parseValue(s string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("malformed value: %s", s)
+	}
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("value %d is out of range [%d, %d]", v, b.min, b.max)
+	}
+	return v, nil
+}
+
+// parseField parses a comma-separated list of `*`, `?`, values, ranges and steps into the bits of matching values.
+// It also returns true if the field is unrestricted.
+func parseField(field string, b bounds) (uint64, bool, error) {
+	if field == "*" || field == "?" {
+		var bits uint64
+		for v := b.min; v <= b.max; v++ {
+			bits |= 1 << uint(v)
+		}
+		return bits, true, nil
+	}
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepExpr)
+			if err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("malformed step: %s", part)
+			}
+		}
+
+		var lo, hi int
+		switch lower, upper, isRange := strings.Cut(rangeExpr, "-"); {
+		case rangeExpr == "*" || rangeExpr == "?":
+			lo, hi = b.min, b.max
+		case isRange:
+			var err error
+			if lo, err = parseValue(lower, b); err != nil {
+				return 0, false, err
+			}
+			if hi, err = parseValue(upper, b); err != nil {
+				return 0, false, err
+			}
+			if lo > hi {
+				return 0, false, fmt.Errorf("beginning of range is after the end: %s", part)
+			}
+		default:
+			var err error
+			if lo, err = parseValue(rangeExpr, b); err != nil {
+				return 0, false, err
+			}
+			hi = lo
+			if hasStep {
+				hi = b.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, false, nil
+}
+
+// parseDomField parses the field of day of month with `L` and `W`.
+func parseDomField(field string) (domSpec, error) {
+	var spec domSpec
+	var standard []string
+	for _, part := range strings.Split(field, ",") {
+		switch {
+		case part == "L":
+			spec.lastOffsets = append(spec.lastOffsets, 0)
+		case part == "LW":
+			spec.lastWeekday = true
+		case strings.HasPrefix(part, "L-"):
+			offset, err := strconv.Atoi(part[2:])
+			if err != nil || offset < 0 || offset > 30 {
+				return spec, fmt.Errorf("malformed offset from the last day: %s", part)
+			}
+			spec.lastOffsets = append(spec.lastOffsets, offset)
+		case strings.HasSuffix(part, "W"):
+			day, err := parseValue(strings.TrimSuffix(part, "W"), domBounds)
+			if err != nil {
+				return spec, err
+			}
+			spec.nearest = append(spec.nearest, day)
+		default:
+			standard = append(standard, part)
+		}
+	}
+	if len(standard) > 0 {
+		var err error
+		spec.bits, spec.star, err = parseField(strings.Join(standard, ","), domBounds)
+		if err != nil {
+			return spec, err
+		}
+		if spec.star && len(standard) != len(strings.Split(field, ",")) {
+			return spec, fmt.Errorf("`*` cannot be combined with the other values: %s", field)
+		}
+	}
+	return spec, nil
+}
+
+// parseDowField parses the field of day of week with `L` and `#`.
+func parseDowField(field string) (dowSpec, error) {
+	var spec dowSpec
+	var standard []string
+	for _, part := range strings.Split(field, ",") {
+		switch {
+		case len(part) > 1 && strings.HasSuffix(part, "L"):
+			weekday, err := parseValue(strings.TrimSuffix(part, "L"), weekdayBounds)
+			if err != nil {
+				return spec, err
+			}
+			spec.last = append(spec.last, weekday%7)
+		case strings.Contains(part, "#"):
+			weekdayExpr, nthExpr, _ := strings.Cut(part, "#")
+			weekday, err := parseValue(weekdayExpr, weekdayBounds)
+			if err != nil {
+				return spec, err
+			}
+			nth, err := strconv.Atoi(nthExpr)
+			if err != nil || nth < 1 || nth > 5 {
+				return spec, fmt.Errorf("malformed occurrence of weekday: %s", part)
+			}
+			spec.nth = append(spec.nth, [2]int{weekday % 7, nth})
+		default:
+			standard = append(standard, part)
+		}
+	}
+	if len(standard) > 0 {
+		var err error
+		spec.bits, spec.star, err = parseField(strings.Join(standard, ","), weekdayBounds)
+		if err != nil {
+			return spec, err
+		}
+		if spec.star && len(standard) != len(strings.Split(field, ",")) {
+			return spec, fmt.Errorf("`*` cannot be combined with the other values: %s", field)
+		}
+		// both of 0 and 7 mean Sunday
+		if spec.bits&(1<<7) != 0 {
+			spec.bits = spec.bits&^(1<<7) | 1
+		}
+	}
+	return spec, nil
+}
+
+// daysIn returns the number of days in the month of `t`.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday returns the weekday nearest to `day` in the month of `t` without crossing the month.
+func nearestWeekday(t time.Time, day int) int {
+	last := daysIn(t)
+	if day > last {
+		return 0
+	}
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
+func (d *domSpec) match(t time.Time) bool {
+	day := t.Day()
+	if d.bits&(1<<uint(day)) != 0 {
+		return true
+	}
+	last := daysIn(t)
+	for _, offset := range d.lastOffsets {
+		if day == last-offset {
+			return true
+		}
+	}
+	for _, n := range d.nearest {
+		if day == nearestWeekday(t, n) {
+			return true
+		}
+	}
+	if d.lastWeekday {
+		if day == nearestWeekday(t, last) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dowSpec) match(t time.Time) bool {
+	weekday := int(t.Weekday())
+	if d.bits&(1<<uint(weekday)) != 0 {
+		return true
+	}
+	for _, w := range d.last {
+		if weekday == w && t.Day()+7 > daysIn(t) {
+			return true
+		}
+	}
+	for _, n := range d.nth {
+		if weekday == n[0] && (t.Day()-1)/7+1 == n[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDay returns true if the day of `t` matches the schedule.
+func (s *specSchedule) matchDay(t time.Time) bool {
+	if s.dom.star || s.dow.star {
+		return s.dom.match(t) && s.dow.match(t)
+	}
+	return s.dom.match(t) || s.dow.match(t)
+}
+
+// Next returns the next time to fire after `t` in the time zone of `t`.
+// It returns zero value if the schedule does not fire in 5 years.
+func (s *specSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	// start from the next second
+	t = t.Add(time.Second - time.Duration(t.Nanosecond())*time.Nanosecond)
+	added := false
+	yearLimit := t.Year() + 5
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.matchDay(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for s.second&(1<<uint(t.Second())) == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+	return t
+}
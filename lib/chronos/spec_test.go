@@ -0,0 +1,215 @@
+package chronos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronSpec(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, 1, 10, 12, 34, 56, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		spec    string
+		want    []time.Time
+		wantErr bool
+	}{
+		{
+			name: "5 fields",
+			spec: "30 * * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 10, 13, 30, 0, 0, time.UTC),
+				time.Date(2024, 1, 10, 14, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "6 fields",
+			spec: "15 30 * * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 10, 13, 30, 15, 0, time.UTC),
+				time.Date(2024, 1, 10, 14, 30, 15, 0, time.UTC),
+			},
+		},
+		{
+			name: "lists, ranges and steps",
+			spec: "0 9-17/4,20 * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 10, 13, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 10, 17, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 10, 20, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "names",
+			spec: "0 0 * FEB sun",
+			want: []time.Time{
+				time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 11, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "7 as Sunday",
+			spec: "0 0 ? * 7",
+			want: []time.Time{
+				time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "day of month or day of week",
+			spec: "0 0 15 * MON",
+			want: []time.Time{
+				time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last day of month",
+			spec: "0 9 L * *",
+			want: []time.Time{
+				time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "offset from last day of month",
+			spec: "0 9 L-2 * *",
+			want: []time.Time{
+				time.Date(2024, 1, 29, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 27, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "nearest weekday",
+			// 2024-06-01 is Saturday and 2024-06-30 is Sunday
+			spec: "0 9 1W,30W 6 *",
+			want: []time.Time{
+				time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 6, 28, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last weekday of month",
+			// 2024-03-31 is Sunday
+			spec: "0 9 LW 3 *",
+			want: []time.Time{
+				time.Date(2024, 3, 29, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last weekday of week in month",
+			spec: "0 9 * * 5L",
+			want: []time.Time{
+				time.Date(2024, 1, 26, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 23, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "nth weekday of week in month",
+			spec: "0 9 ? * TUE#2",
+			want: []time.Time{
+				time.Date(2024, 2, 13, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			want: []time.Time{
+				time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "descriptor",
+			spec: "@daily",
+			want: []time.Time{
+				time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "every",
+			spec: "@every 1h30m",
+			want: []time.Time{
+				time.Date(2024, 1, 10, 14, 4, 56, 0, time.UTC),
+				time.Date(2024, 1, 10, 15, 34, 56, 0, time.UTC),
+			},
+		},
+		{
+			name: "reboot",
+			spec: "@reboot",
+			want: []time.Time{{}},
+		},
+		{
+			name:    "too few fields",
+			spec:    "* * * *",
+			wantErr: true,
+		},
+		{
+			name:    "out of range",
+			spec:    "0 24 * * *",
+			wantErr: true,
+		},
+		{
+			name:    "reversed range",
+			spec:    "0 10-9 * * *",
+			wantErr: true,
+		},
+		{
+			name:    "unknown descriptor",
+			spec:    "@fortnightly",
+			wantErr: true,
+		},
+		{
+			name:    "too short interval",
+			spec:    "@every 10ms",
+			wantErr: true,
+		},
+		{
+			name:    "malformed nth weekday",
+			spec:    "0 0 * * 1#6",
+			wantErr: true,
+		},
+		{
+			name:    "never fires",
+			spec:    "0 0 30 2 *",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCronSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			next := from
+			for _, want := range tt.want {
+				next = s.Next(next)
+				if !next.Equal(want) {
+					t.Fatalf("unexpected next time. want: %s, got: %s", want, next)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	for _, spec := range []string{"0 H * * *", "0 0 H(9-17) * * *", "CRON_TZ=Asia/Tokyo 0 9 * * 1-5", "@reboot"} {
+		if err := validateSchedule(spec); err != nil {
+			t.Errorf("unexpected error for %q: %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"", "bad", "0 H(0-99) * * *", "CRON_TZ=Asia/Tokyo"} {
+		if err := validateSchedule(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package chronos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// WindowState is the enum of the states of a task against its active period. See `Task.StartAt`.
+type WindowState string
+
+const (
+	// WindowStatePending is the state of the task before `StartAt` or `RunOnceAt`.
+	WindowStatePending WindowState = "pending"
+	// WindowStateActive is the state of the task scheduled now.
+	WindowStateActive WindowState = "active"
+	// WindowStateExpired is the state of the task after `EndAt` or `RunOnceAt`.
+	WindowStateExpired WindowState = "expired"
+)
+
+// parseTimestamp parses the timestamp in RFC 3339. It returns zero value for the empty string.
+func parseTimestamp(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+	return t, nil
+}
+
+// window returns the active period of the task.
+// zero value of `start` or `end` means the period is not bounded on the side.
+// For `RunOnceAt`, both of them are the time to execute the task.
+func (t *Task) window() (start time.Time, end time.Time, err error) {
+	if t.RunOnceAt != "" {
+		at, err := parseTimestamp(t.RunOnceAt)
+		return at, at, err
+	}
+	if start, err = parseTimestamp(t.StartAt); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end, err = parseTimestamp(t.EndAt); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("`end_at` is before `start_at`: %s < %s", t.EndAt, t.StartAt)
+	}
+	return start, end, nil
+}
+
+// windowState returns the state of the task against its active period at `now`.
+// The task whose period is malformed is regarded as active, since it is rejected by the scheduler anyway.
+func (t *Task) windowState(now time.Time) WindowState {
+	start, end, err := t.window()
+	switch {
+	case err != nil:
+		return WindowStateActive
+	case t.RunOnceAt != "" && !now.Before(start):
+		return WindowStateExpired
+	case now.Before(start):
+		return WindowStatePending
+	case !end.IsZero() && now.After(end):
+		return WindowStateExpired
+	}
+	return WindowStateActive
+}
+
+// scheduled returns true if the task is executed by the scheduler.
+func (t *Task) scheduled() bool {
+	return t.Schedule != "" || t.RunOnceAt != ""
+}
+
+// onceSchedule is the schedule which fires only once at the time.
+type onceSchedule struct {
+	at time.Time
+}
+
+// Next returns the time to fire if it is after `t`, or zero value otherwise.
+func (s *onceSchedule) Next(t time.Time) time.Time {
+	if s.at.After(t) {
+		return s.at.In(t.Location())
+	}
+	return time.Time{}
+}
+
+// windowSchedule is the schedule which fires only in the period between `start` and `end`.
+type windowSchedule struct {
+	schedule   cron.Schedule
+	start, end time.Time
+}
+
+// Next returns the next time to fire after `t` within the period.
+// It returns zero value after the period.
+func (s *windowSchedule) Next(t time.Time) time.Time {
+	if !s.start.IsZero() && t.Before(s.start) {
+		// the schedule may fire just at `start`
+		t = s.start.Add(-time.Nanosecond).In(t.Location())
+	}
+	next := s.schedule.Next(t)
+	if !s.end.IsZero() && next.After(s.end) {
+		return time.Time{}
+	}
+	return next
+}
@@ -0,0 +1,111 @@
+package chronos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskWindowState(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		task *Task
+		want WindowState
+	}{
+		{
+			name: "unbounded",
+			task: &Task{Schedule: "@daily"},
+			want: WindowStateActive,
+		},
+		{
+			name: "before start_at",
+			task: &Task{Schedule: "@daily", StartAt: "2024-06-02T00:00:00Z"},
+			want: WindowStatePending,
+		},
+		{
+			name: "within period",
+			task: &Task{Schedule: "@daily", StartAt: "2024-05-01T00:00:00Z", EndAt: "2024-06-30T00:00:00Z"},
+			want: WindowStateActive,
+		},
+		{
+			name: "after end_at",
+			task: &Task{Schedule: "@daily", EndAt: "2024-06-01T20:00:00+09:00"},
+			want: WindowStateExpired,
+		},
+		{
+			name: "before run_once_at",
+			task: &Task{RunOnceAt: "2024-06-01T12:30:00Z"},
+			want: WindowStatePending,
+		},
+		{
+			name: "after run_once_at",
+			task: &Task{RunOnceAt: "2024-06-01T12:00:00Z"},
+			want: WindowStateExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.windowState(now); got != tt.want {
+				t.Errorf("unexpected state. want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTaskScheduleWindow(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		task    *Task
+		want    []time.Time
+		wantErr bool
+	}{
+		{
+			name: "start_at and end_at",
+			task: &Task{Schedule: "0 0 * * *", StartAt: "2024-06-10T00:00:00Z", EndAt: "2024-06-11T12:00:00Z"},
+			want: []time.Time{
+				time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC),
+				{},
+			},
+		},
+		{
+			name: "run_once_at",
+			task: &Task{RunOnceAt: "2024-06-15T09:30:00+09:00"},
+			want: []time.Time{
+				time.Date(2024, 6, 15, 0, 30, 0, 0, time.UTC),
+				{},
+			},
+		},
+		{
+			name:    "end_at before start_at",
+			task:    &Task{Schedule: "0 0 * * *", StartAt: "2024-06-10T00:00:00Z", EndAt: "2024-06-01T00:00:00Z"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.task.schedule("test", time.UTC)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			next := from
+			for _, want := range tt.want {
+				next = s.Next(next)
+				if !next.Equal(want) {
+					t.Fatalf("unexpected next time. want: %s, got: %s", want, next)
+				}
+				if next.IsZero() {
+					break
+				}
+			}
+		})
+	}
+}
@@ -7,59 +7,224 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/robfig/cron"
 	"github.com/xruins/chronos/lib/logger"
 )
 
 // Worker is the implementation of Chronos worker.
 type Worker struct {
-	conf   *Config
-	jobs   []*Job
-	logger logger.Logger
-	loc    *time.Location
+	conf       *Config
+	jobs       []*Job
+	history    HistoryStore
+	logger     logger.Logger
+	loc        *time.Location
+	mu         sync.RWMutex
+	cron       *cron.Cron
+	server     *http.Server
+	registry   *prometheus.Registry
+	metrics    *metrics
+	notifier   *notifier
+	runCtx     context.Context
+	cancelRuns context.CancelCauseFunc
+	runs       sync.WaitGroup
+	closing    bool
 }
 
+var (
+	// ErrJobsInterrupted is the error returned by `Worker.Run` when running Jobs were interrupted on shutdown.
+	ErrJobsInterrupted = errors.New("running tasks were interrupted by shutdown")
+
+	// errShutdown is the cause of the cancellation of running Jobs on shutdown.
+	errShutdown = errors.New("worker is shutting down")
+)
+
+const (
+	// DefaultShutdownTimeout is the seconds to wait for running tasks on shutdown when `Config.ShutdownTimeout` is not specified.
+	DefaultShutdownTimeout = 30
+
+	// healthCheckShutdownTimeout is the time to wait for HealthCheck server and notifications to finish the requests in process on shutdown.
+	healthCheckShutdownTimeout = 5 * time.Second
+)
+
 // NewWorker returns an instance of `Worker`.
 // It returns error when given malformed config.
 func NewWorker(conf *Config, logger logger.Logger) (*Worker, error) {
+	history, err := NewHistoryStore(conf.History, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create history store: %w", err)
+	}
+
+	loc, err := loadLocation(conf.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancelRuns := context.WithCancelCause(context.Background())
+	w := &Worker{
+		conf:       conf,
+		history:    history,
+		logger:     logger,
+		loc:        loc,
+		registry:   prometheus.NewRegistry(),
+		runCtx:     runCtx,
+		cancelRuns: cancelRuns,
+	}
+	w.metrics = newMetrics(w.registry, w.Jobs, w.nextRun, w.health)
+	w.notifier = newNotifier(func() *Notification {
+		return w.config().Notification
+	}, logger)
+
 	jobs := make([]*Job, 0, len(conf.Tasks))
 	for name, t := range conf.Tasks {
-		jobs = append(jobs, NewJob(name, t, logger))
+		j, err := w.newJob(name, t)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
 	}
+	w.jobs = jobs
+	return w, nil
+}
 
-	loc := time.Local
-	if tz := conf.TimeZone; tz != "" {
-		var err error
-		loc, err = time.LoadLocation(tz)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get timezone: %w", err)
+// loadLocation returns the location for the name of time-zone.
+// It returns `time.Local` for the empty name.
+func loadLocation(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timezone: %w", err)
+	}
+	return loc, nil
+}
+
+// config returns the current config of the Worker.
+func (w *Worker) config() *Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.conf
+}
+
+// Jobs returns the Jobs managed by the Worker.
+func (w *Worker) Jobs() []*Job {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	jobs := make([]*Job, len(w.jobs))
+	copy(jobs, w.jobs)
+	return jobs
+}
+
+// Job returns the Job for the task named `name`.
+// It returns nil if no such task.
+func (w *Worker) Job(name string) *Job {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, j := range w.jobs {
+		if j.name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+// entries returns the snapshot of entries of the cron scheduler.
+// It returns nil if the scheduler is not running.
+func (w *Worker) entries() []*cron.Entry {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.cron == nil {
+		return nil
+	}
+	return w.cron.Entries()
+}
+
+// entry returns the entry of the cron scheduler for the Job.
+// It returns nil if the Job is not scheduled.
+func (w *Worker) entry(j *Job) *cron.Entry {
+	for _, e := range w.entries() {
+		if s, ok := e.Job.(*scheduledJob); ok && s.job == j {
+			return e
 		}
 	}
+	return nil
+}
 
-	return &Worker{
-		conf:   conf,
-		jobs:   jobs,
-		logger: logger,
-		loc:    loc,
-	}, nil
+// nextRun returns the time when the Job is scheduled to be executed next.
+// It returns zero value if the Job is not scheduled.
+func (w *Worker) nextRun(j *Job) time.Time {
+	e := w.entry(j)
+	if e == nil {
+		return time.Time{}
+	}
+	return e.Next
+}
+
+// health returns the state of the Job and the reason why it is unhealthy, taking the staleness into account.
+func (w *Worker) health(j *Job) (State, string) {
+	if state := j.state(); state != StateHealthy {
+		return state, j.HealthReason()
+	}
+
+	w.mu.RLock()
+	loc := w.loc
+	w.mu.RUnlock()
+	var schedule cron.Schedule
+	if j.task.DetectStaleness {
+		// the schedule has been validated on registration to the scheduler
+		schedule, _ = j.task.schedule(j.name, loc)
+	}
+	if reason := j.staleness(time.Now().In(loc), schedule); reason != "" {
+		return StateUnhealthy, reason
+	}
+	return StateHealthy, ""
+}
+
+// newJob returns a Job for the task whose state is restored from the history store.
+func (w *Worker) newJob(name string, task *Task) (*Job, error) {
+	j := NewJob(name, task, w.history, w.logger)
+	j.metrics = w.metrics
+	j.notifier = w.notifier
+	j.onFinish = w.triggerDownstream
+	j.excluded = w.excluded
+	err := j.loadState()
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
 }
 
 type healthCheckResult struct {
 	OK         bool     `json:"ok"`
 	FailedJobs []string `json:"failed_jobs"`
+	PausedJobs []string `json:"paused_jobs,omitempty"`
+	// Reasons are the reasons of failure keyed by the names of failed jobs.
+	Reasons map[string]string `json:"reasons,omitempty"`
 }
 
 func (w *Worker) healthCheckHandler(rw http.ResponseWriter, _ *http.Request) {
-	var failedJobNames []string
-	for _, j := range w.jobs {
-		if !j.IsHealthy() {
+	var failedJobNames, pausedJobNames []string
+	var reasons map[string]string
+	for _, j := range w.Jobs() {
+		if state, reason := w.health(j); state != StateHealthy {
 			failedJobNames = append(failedJobNames, j.name)
+			if reasons == nil {
+				reasons = make(map[string]string)
+			}
+			reasons[j.name] = reason
+		}
+		if j.IsPaused() {
+			pausedJobNames = append(pausedJobNames, j.name)
 		}
 	}
 
-	res := healthCheckResult{}
+	res := healthCheckResult{PausedJobs: pausedJobNames, Reasons: reasons}
 	if len(failedJobNames) > 0 {
 		res.FailedJobs = failedJobNames
 	} else {
@@ -83,63 +248,224 @@ const (
 	HealthCheckEndpoint = "/health"
 )
 
+// Handler returns the HTTP handler which serves HealthCheck and the other APIs.
+func (w *Worker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthCheckEndpoint, w.healthCheckHandler)
+	mux.HandleFunc(TasksEndpoint, w.tasksHandler)
+	mux.HandleFunc(TasksEndpoint+"/", w.taskHandler)
+	mux.Handle(MetricsEndpoint, promhttp.HandlerFor(w.registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
 // ServeHealthCheckServer starts to serve HealthCheck server.
+// It returns nil when the server is closed by `Run` on shutdown.
 func (w *Worker) ServeHealthCheckServer() error {
-	http.HandleFunc(HealthCheckEndpoint, w.healthCheckHandler)
+	conf := w.config().HealthCheck
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", conf.Host, conf.Port),
+		Handler: w.Handler(),
+	}
+	w.mu.Lock()
+	w.server = server
+	w.mu.Unlock()
 
-	err := http.ListenAndServe(
-		fmt.Sprintf("%s:%d", w.conf.HealthCheck.Host, w.conf.HealthCheck.Port),
-		nil,
-	)
+	err := server.ListenAndServe()
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("an error occured when serve HTTP server: %w", err)
 	}
 	return nil
 }
 
-// Run starts periodic execution of Jobs.
-func (w *Worker) Run(ctx context.Context) error {
-	stopCh := make(chan struct{}, 1)
-	if w.conf.HealthCheck != nil {
-		go func() {
-			w.logger.Infof("Healthcheck server started on %s:%d", w.conf.HealthCheck.Host, w.conf.HealthCheck.Port)
-			err := w.ServeHealthCheckServer()
-			if !errors.Is(context.Canceled, err) {
-				w.logger.Fatalf("Healthcheck server stopped. err: %s", err)
-			}
-			stopCh <- struct{}{}
-		}()
+// shutdownHealthCheckServer gracefully shuts down HealthCheck server if it is running.
+func (w *Worker) shutdownHealthCheckServer(ctx context.Context) error {
+	w.mu.RLock()
+	server := w.server
+	w.mu.RUnlock()
+	if server == nil {
+		return nil
 	}
+	return server.Shutdown(ctx)
+}
 
-	c := cron.NewWithLocation(w.loc)
-	w.logger.Infof("Worker started with timezone %s", w.loc)
+// scheduledJob is the cron.Job which runs a Job with the context of the Worker.
+type scheduledJob struct {
+	w   *Worker
+	job *Job
+}
+
+// Run runs the Job unless the Worker is shutting down.
+// The execution is delayed randomly up to `Task.Splay`.
+func (s *scheduledJob) Run() {
+	scheduledAt := time.Now().Truncate(time.Second)
+	if delay := s.job.task.splay(); delay > 0 {
+		s.w.logger.Debugf("Task `%s` is delayed by %s.", s.job.name, delay)
+		s.w.mu.RLock()
+		runCtx := s.w.runCtx
+		s.w.mu.RUnlock()
+		select {
+		case <-time.After(delay):
+		case <-runCtx.Done():
+			return
+		}
+	}
+
+	ctx, ok := s.w.startRun()
+	if !ok {
+		return
+	}
+	defer s.w.runs.Done()
+	s.job.runUnlessPaused(ctx, runCause{trigger: TriggerSchedule, scheduledAt: scheduledAt})
+}
+
+// startRun registers an in-flight run of a Job and returns the context for it.
+// It returns false if the Worker is shutting down.
+// `w.runs.Done` must be called when the run finished.
+func (w *Worker) startRun() (context.Context, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closing {
+		return nil, false
+	}
+	w.runs.Add(1)
+	return w.runCtx, true
+}
+
+// shutdown stops in-flight runs of Jobs and waits for them up to `ShutdownTimeout`.
+// It returns `ErrJobsInterrupted` if any run was in-flight.
+func (w *Worker) shutdown() error {
+	w.mu.Lock()
+	w.closing = true
+	w.mu.Unlock()
+
+	var running []string
+	for _, j := range w.Jobs() {
+		if j.Running() > 0 {
+			running = append(running, j.name)
+		}
+	}
+	if len(running) == 0 {
+		w.cancelRuns(errShutdown)
+		return nil
+	}
+
+	timeout := time.Duration(w.config().ShutdownTimeout) * time.Second
+	if timeout == 0 {
+		timeout = DefaultShutdownTimeout * time.Second
+	}
+	w.logger.Warnf("Worker is sending SIGTERM to running tasks and waiting for them up to %s. tasks: %s", timeout, strings.Join(running, ", "))
+	w.cancelRuns(errShutdown)
 
+	done := make(chan struct{})
+	go func() {
+		w.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		w.logger.Infof("All running tasks finished.")
+	case <-time.After(timeout):
+		w.logger.Errorf("Worker gave up waiting for running tasks to finish.")
+	}
+	return ErrJobsInterrupted
+}
+
+// newCron returns a cron scheduler which has entries for `jobs`.
+// It returns error if the schedule of any Job is malformed.
+func (w *Worker) newCron(jobs []*Job, loc *time.Location) (*cron.Cron, error) {
+	c := cron.NewWithLocation(loc)
 	c.ErrorLog = log.Default()
 
-	for _, j := range w.jobs {
-		err := c.AddJob(j.task.Schedule, j)
+	for _, j := range jobs {
+		if !j.task.scheduled() {
+			w.logger.Infof("Task `%s` has been registered without schedule.", j.name)
+			continue
+		}
+		schedule, err := j.task.schedule(j.name, loc)
 		if err != nil {
-			return fmt.Errorf("failed to add Task `%s`. err: %s", j.name, err)
+			return nil, fmt.Errorf("failed to add Task `%s`. err: %s", j.name, err)
 		}
+		c.Schedule(schedule, &scheduledJob{w: w, job: j})
 		w.logger.Infof("Task `%s` has been registered. schedule: %s", j.name, j.task.Schedule)
 	}
+	return c, nil
+}
 
+// Run starts periodic execution of Jobs.
+// When `ctx` is cancelled, it stops scheduling, terminates running Jobs and shuts down HealthCheck server.
+// It returns nil on graceful shutdown, or `ErrJobsInterrupted` if any Job was interrupted.
+func (w *Worker) Run(ctx context.Context) error {
+	stopCh := make(chan error, 1)
+	if conf := w.config().HealthCheck; conf != nil {
+		go func() {
+			w.logger.Infof("Healthcheck server started on %s:%d", conf.Host, conf.Port)
+			err := w.ServeHealthCheckServer()
+			if err != nil {
+				w.logger.Errorf("Healthcheck server stopped. err: %s", err)
+				stopCh <- err
+			}
+		}()
+	}
+
+	w.mu.Lock()
+	c, err := w.newCron(w.jobs, w.loc)
+	if err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	w.logger.Infof("Worker started with timezone %s", w.loc)
+	w.cron = c
+	startedAt := time.Now()
 	c.Start()
-	defer c.Stop()
+	w.mu.Unlock()
 
 	for _, e := range c.Entries() {
-		job, ok := e.Job.(*Job)
+		job, ok := e.Job.(*scheduledJob)
 		if !ok {
+			c.Stop()
 			return fmt.Errorf("unexpected type of Job inside Entry. got: %T", e.Job)
 		}
 
-		w.logger.Infof("Task `%s` will be executed in %s at first", job.name, e.Next)
+		if isReboot(e.Schedule) {
+			if job.job.task.windowState(startedAt) != WindowStateActive {
+				w.logger.Infof("Task `%s` is out of its active period. skipped the execution on startup.", job.job.name)
+				continue
+			}
+			// `@reboot` is never fired by the scheduler, so execute it once here.
+			w.logger.Infof("Task `%s` will be executed on startup", job.job.name)
+			go job.Run()
+			continue
+		}
+		if e.Next.IsZero() {
+			w.logger.Infof("Task `%s` will not be executed by the scheduler since its active period has ended.", job.job.name)
+			continue
+		}
+		w.logger.Infof("Task `%s` will be executed in %s at first", job.job.name, e.Next)
+		go job.catchup(e.Schedule, startedAt)
 	}
 
 	select {
 	case <-ctx.Done():
-		return fmt.Errorf("cancelled by context. err: %w", ctx.Err())
-	case <-stopCh:
-		return errors.New("cron scheduler stopped")
+		w.logger.Infof("Worker is shutting down. reason: %s", context.Cause(ctx))
+	case err = <-stopCh:
+		err = fmt.Errorf("healthcheck server stopped: %w", err)
+	}
+
+	// stop the current scheduler, which may have been replaced by `Reload`
+	w.mu.Lock()
+	w.cron.Stop()
+	w.mu.Unlock()
+	shutdownErr := w.shutdown()
+
+	serverCtx, cancel := context.WithTimeout(context.Background(), healthCheckShutdownTimeout)
+	defer cancel()
+	w.notifier.wait(serverCtx)
+	if e := w.shutdownHealthCheckServer(serverCtx); e != nil {
+		w.logger.Warnf("failed to shut down healthcheck server gracefully: %s", e)
+	}
+
+	if err != nil {
+		return err
 	}
+	return shutdownErr
 }
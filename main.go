@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -53,6 +57,186 @@ var healthCheckCmd = &cobra.Command{
 	},
 }
 
+func init() {
+	triggerCmd.PersistentFlags().IntP("timeout", "t", 0, "timeout to wait for the completion of the task in seconds (0: no timeout)")
+	triggerCmd.PersistentFlags().BoolP("wait", "w", false, "wait for the completion of the task and exit with its status")
+	triggerCmd.PersistentFlags().StringP("token", "k", os.Getenv("CHRONOS_TOKEN"), "bearer token for the API (default: $CHRONOS_TOKEN)")
+}
+
+var triggerCmd = &cobra.Command{
+	Use:     "trigger",
+	Example: "chronos trigger http://localhost:8080 hello",
+	Short:   "Execute a task on Chronos worker immediately",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			cmd.Help()
+			os.Exit(1)
+		}
+
+		timeout, err := cmd.Flags().GetInt("timeout")
+		if err != nil {
+			log.Fatalf("failed to get the value of `timeout` option: %s", err)
+		}
+		wait, err := cmd.Flags().GetBool("wait")
+		if err != nil {
+			log.Fatalf("failed to get the value of `wait` option: %s", err)
+		}
+		token, err := cmd.Flags().GetString("token")
+		if err != nil {
+			log.Fatalf("failed to get the value of `token` option: %s", err)
+		}
+
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+			defer cancel()
+		}
+		u, err := url.Parse(args[0])
+		if err != nil {
+			log.Fatalf("failed to parse URL: %s", err)
+		}
+		name := args[1]
+		client := chronos.NewClient(http.DefaultClient).WithToken(token)
+		execution, err := client.Trigger(ctx, u, name, wait)
+		if err != nil {
+			log.Fatalf("failed to trigger the task: %s", err)
+		}
+		if !wait {
+			fmt.Printf("Task `%s` triggered\n", name)
+			os.Exit(0)
+		}
+
+		if execution.Status != chronos.ExecutionStatusSucceeded {
+			fmt.Fprintf(os.Stderr, "Task `%s` failed. exit code: %d, err: %s\n", name, execution.ExitCode, execution.Error)
+			if execution.ExitCode > 0 {
+				os.Exit(execution.ExitCode)
+			}
+			os.Exit(1)
+		}
+		fmt.Printf("Task `%s` finished successfully\n", name)
+		os.Exit(0)
+	},
+}
+
+// newPauseCmd returns the command to pause or resume a task.
+func newPauseCmd(pause bool) *cobra.Command {
+	use, short := "resume", "Resume the scheduled executions of a task on Chronos worker"
+	if pause {
+		use, short = "pause", "Pause the scheduled executions of a task on Chronos worker"
+	}
+
+	cmd := &cobra.Command{
+		Use:     use,
+		Example: fmt.Sprintf("chronos %s http://localhost:8080 hello", use),
+		Short:   short,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			timeout, err := cmd.Flags().GetInt("timeout")
+			if err != nil {
+				log.Fatalf("failed to get the value of `timeout` option: %s", err)
+			}
+			token, err := cmd.Flags().GetString("token")
+			if err != nil {
+				log.Fatalf("failed to get the value of `token` option: %s", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+			defer cancel()
+			u, err := url.Parse(args[0])
+			if err != nil {
+				log.Fatalf("failed to parse URL: %s", err)
+			}
+			name := args[1]
+
+			client := chronos.NewClient(http.DefaultClient).WithToken(token)
+			if pause {
+				_, err = client.Pause(ctx, u, name)
+			} else {
+				_, err = client.Resume(ctx, u, name)
+			}
+			if err != nil {
+				log.Fatalf("failed to %s the task: %s", use, err)
+			}
+			fmt.Printf("Task `%s` %sd\n", name, use)
+			os.Exit(0)
+		},
+	}
+	cmd.PersistentFlags().IntP("timeout", "t", 30, "timeout to invoke the API in seconds")
+	cmd.PersistentFlags().StringP("token", "k", os.Getenv("CHRONOS_TOKEN"), "bearer token for the API (default: $CHRONOS_TOKEN)")
+	return cmd
+}
+
+func init() {
+	workerCmd.PersistentFlags().BoolP("watch", "w", false, "reload config file automatically when it changed")
+	workerCmd.PersistentFlags().Int("watch-interval", 5, "interval to check the change of config file in seconds")
+}
+
+func init() {
+	validateCmd.PersistentFlags().StringP("format", "f", "text", "output format (text or json)")
+	validateCmd.PersistentFlags().Bool("strict", false, "exit with non-zero status on warnings as well as errors")
+}
+
+var validateCmd = &cobra.Command{
+	Use:     "validate",
+	Example: "chronos validate config.yml",
+	Short:   "Validate config file of Chronos worker",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Help()
+			os.Exit(1)
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			log.Fatalf("failed to get the value of `format` option: %s", err)
+		}
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			log.Fatalf("failed to get the value of `strict` option: %s", err)
+		}
+
+		issues := chronos.LintConfig(args[0])
+		failed := chronos.HasError(issues) || (strict && len(issues) > 0)
+
+		switch format {
+		case "json":
+			if issues == nil {
+				issues = []*chronos.Issue{}
+			}
+			b, err := json.MarshalIndent(map[string]interface{}{
+				"file":   args[0],
+				"valid":  !failed,
+				"issues": issues,
+			}, "", "  ")
+			if err != nil {
+				log.Fatalf("failed to marshal JSON: %s", err)
+			}
+			fmt.Println(string(b))
+		case "text":
+			for _, i := range issues {
+				fmt.Println(i)
+			}
+			if !failed {
+				fmt.Printf("%s: OK\n", args[0])
+			}
+		default:
+			log.Fatalf("unknown format: %s", format)
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+// exitCodeInterrupted is the exit code of worker command when running tasks were interrupted on shutdown.
+const exitCodeInterrupted = 2
+
 var workerCmd = &cobra.Command{
 	Use:   "worker",
 	Short: "Start Chronos worker",
@@ -62,14 +246,19 @@ var workerCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		confName := args[0]
-		i, err := os.Open(confName)
+		watch, err := cmd.Flags().GetBool("watch")
 		if err != nil {
-			log.Fatalf("failed to open config file: %s", err)
+			log.Fatalf("failed to get the value of `watch` option: %s", err)
 		}
-		conf, err := chronos.NewConfig(i, confName)
+		watchInterval, err := cmd.Flags().GetInt("watch-interval")
 		if err != nil {
-			log.Fatalf("failed to parse config file: %s", err)
+			log.Fatalf("failed to get the value of `watch-interval` option: %s", err)
+		}
+
+		confName := args[0]
+		conf, err := chronos.LoadConfig(confName)
+		if err != nil {
+			log.Fatalf("failed to load config file: %s", err)
 		}
 
 		loc := time.Local
@@ -88,8 +277,37 @@ var workerCmd = &cobra.Command{
 		if err != nil {
 			l.Fatalf("failed to start worker: %s", err)
 		}
-		ctx := context.Background()
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		reload := func() {
+			l.Infof("Reloading config file %s", confName)
+			err := w.ReloadFile(confName)
+			if err != nil {
+				l.Errorf("failed to reload config. the current config is kept: %s", err)
+			}
+		}
+		hupCh := make(chan os.Signal, 1)
+		signal.Notify(hupCh, syscall.SIGHUP)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-hupCh:
+					reload()
+				}
+			}
+		}()
+		if watch {
+			go chronos.WatchFile(ctx, confName, time.Duration(watchInterval)*time.Second, reload)
+		}
+
 		err = w.Run(ctx)
+		if errors.Is(err, chronos.ErrJobsInterrupted) {
+			l.Warnf("Worker finished with interrupting running tasks")
+			os.Exit(exitCodeInterrupted)
+		}
 		if err != nil {
 			l.Fatalf("failed to run worker: %s", err)
 		}
@@ -106,7 +324,7 @@ var rootCmd = &cobra.Command{
 }
 
 func init() {
-	rootCmd.AddCommand(workerCmd, healthCheckCmd)
+	rootCmd.AddCommand(workerCmd, healthCheckCmd, triggerCmd, newPauseCmd(true), newPauseCmd(false), validateCmd)
 }
 
 func main() {